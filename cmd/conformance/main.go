@@ -0,0 +1,45 @@
+// Command conformance runs the engine.Engine conformance suite against every
+// backend: reddit.RedditEngine, redditactor.RedditActor and the redditclone
+// manager actors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"reddit_clone/engines"
+	"redditclone/pkg/engine"
+	"redditclone/pkg/engine/conformance"
+	"redditclone/pkg/engine/managers"
+)
+
+func main() {
+	only := flag.String("backend", "", "run only the named backend (reddit, redditactor, managers)")
+	flag.Parse()
+
+	backends := []struct {
+		name string
+		new  func() (engine.Engine, error)
+	}{
+		{"reddit", func() (engine.Engine, error) { return engines.NewReddit(), nil }},
+		{"redditactor", func() (engine.Engine, error) { return engines.NewRedditActor(), nil }},
+		{"managers", func() (engine.Engine, error) { return managers.New() }},
+	}
+
+	passed := true
+	for _, backend := range backends {
+		if *only != "" && *only != backend.name {
+			continue
+		}
+		if !conformance.Print(os.Stdout, backend.name, conformance.Run(backend.new)) {
+			passed = false
+		}
+	}
+
+	if !passed {
+		fmt.Println("FAIL")
+		os.Exit(1)
+	}
+	fmt.Println("PASS")
+}
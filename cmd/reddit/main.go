@@ -0,0 +1,27 @@
+package main
+
+import (
+	"reddit_clone/reddit"
+)
+
+func main() {
+	engine := reddit.NewRedditEngine()
+	defer engine.Close()
+
+	// Register users
+	engine.RegisterUser("alice")
+	engine.RegisterUser("bob")
+
+	// Create and join subreddit
+	engine.CreateSubreddit("golang", "All about Go programming", "alice")
+	engine.JoinSubreddit("bob", "golang")
+
+	// Create post and comments
+	postID, _ := engine.CreatePost("golang", "alice", "Actor models are awesome!")
+	commentID, _ := engine.CreateComment(postID, 0, "bob", "Totally agree!")
+	engine.CreateComment(postID, commentID, "alice", "Thanks!")
+
+	// Vote and send DMs
+	engine.Vote("bob", "post", postID, 1)
+	engine.SendDM("alice", "bob", "Thanks for the support!")
+}
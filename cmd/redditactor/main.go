@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"time"
+	"github.com/asynkron/protoactor-go/actor"
+	"reddit_clone/redditactor"
+)
+
+func main() {
+	system := actor.NewActorSystem()
+	props := actor.PropsFromProducer(redditactor.NewRedditActor)
+	pid := system.Root.Spawn(props)
+
+	// Example usage
+	context := system.Root
+
+	// Register a user
+	result, _ := context.RequestFuture(pid, &redditactor.RegisterUserMsg{Username: "testuser"}, 5*time.Second).Result()
+	userID := result.(string)
+
+	// Create a subreddit
+	context.RequestFuture(pid, &redditactor.CreateSubredditMsg{
+		Name:        "programming",
+		Description: "Discussion about programming",
+	}, 5*time.Second).Wait()
+
+	// Join subreddit
+	context.RequestFuture(pid, &redditactor.JoinSubredditMsg{
+		UserID:        userID,
+		SubredditName: "programming",
+	}, 5*time.Second).Wait()
+
+	// Create a post
+	postResult, _ := context.RequestFuture(pid, &redditactor.CreatePostMsg{
+		UserID:        userID,
+		SubredditName: "programming",
+		Title:         "Hello Proto.Actor",
+		Content:       "This is my first post using Proto.Actor",
+	}, 5*time.Second).Result()
+
+	fmt.Printf("Created post with ID: %s\n", postResult)
+}
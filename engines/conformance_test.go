@@ -0,0 +1,40 @@
+package engines_test
+
+import (
+	"testing"
+
+	"reddit_clone/engines"
+	"redditclone/pkg/engine"
+	"redditclone/pkg/engine/conformance"
+	"redditclone/pkg/engine/managers"
+)
+
+// TestConformance runs the engine.Engine conformance suite against every
+// backend, as cmd/conformance does, each case on a fresh engine.
+func TestConformance(t *testing.T) {
+	backends := []struct {
+		name string
+		new  func() (engine.Engine, error)
+	}{
+		{"reddit", func() (engine.Engine, error) { return engines.NewReddit(), nil }},
+		{"redditactor", func() (engine.Engine, error) { return engines.NewRedditActor(), nil }},
+		{"managers", func() (engine.Engine, error) { return managers.New() }},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			for _, c := range conformance.Cases {
+				t.Run(c.Name, func(t *testing.T) {
+					e, err := backend.new()
+					if err != nil {
+						t.Fatalf("starting engine: %v", err)
+					}
+					defer e.Close()
+					if err := c.Run(e); err != nil {
+						t.Error(err)
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Package engines adapts the engines in this module to redditclone's
+// engine.Engine interface.
+package engines
+
+import (
+	"errors"
+
+	"redditclone/pkg/engine"
+)
+
+// adaptedError keeps an engine's own error text while also matching the
+// equivalent engine.Engine sentinel.
+type adaptedError struct {
+	err  error
+	kind error
+}
+
+func (e *adaptedError) Error() string   { return e.err.Error() }
+func (e *adaptedError) Unwrap() []error { return []error{e.err, e.kind} }
+
+// adaptError maps an engine's notFound and alreadyExists errors onto the
+// engine.Engine ones.
+func adaptError(err, notFound, alreadyExists error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, notFound):
+		return &adaptedError{err: err, kind: engine.ErrNotFound}
+	case errors.Is(err, alreadyExists):
+		return &adaptedError{err: err, kind: engine.ErrAlreadyExists}
+	}
+	return err
+}
@@ -0,0 +1,206 @@
+package engines
+
+import (
+	"fmt"
+
+	"reddit_clone/reddit"
+	"redditclone/pkg/engine"
+)
+
+// Reddit adapts reddit.RedditEngine. Usernames and subreddit names are used
+// as IDs; posts, comments and DMs get a "post_", "comment_" or "dm_" prefix
+// on their numeric IDs. Posts have no title, so titles are dropped.
+type Reddit struct {
+	engine *reddit.RedditEngine
+}
+
+var _ engine.Engine = (*Reddit)(nil)
+
+func NewReddit() *Reddit {
+	return &Reddit{engine: reddit.NewRedditEngine()}
+}
+
+func (r *Reddit) Close() {
+	r.engine.Close()
+}
+
+func (r *Reddit) RegisterUser(username string) (string, error) {
+	if err := r.engine.RegisterUser(username); err != nil {
+		return "", redditError(err)
+	}
+	return username, nil
+}
+
+func (r *Reddit) GetUser(userID string) (engine.User, error) {
+	user, err := r.engine.GetUser(userID)
+	if err != nil {
+		return engine.User{}, redditError(err)
+	}
+	return engine.User{ID: user.Username, Username: user.Username, Karma: user.Karma}, nil
+}
+
+func (r *Reddit) CreateSubreddit(creatorID, name, description string) (string, error) {
+	if err := r.engine.CreateSubreddit(name, description, creatorID); err != nil {
+		return "", redditError(err)
+	}
+	return name, nil
+}
+
+func (r *Reddit) JoinSubreddit(userID, subredditID string) error {
+	return redditError(r.engine.JoinSubreddit(userID, subredditID))
+}
+
+func (r *Reddit) LeaveSubreddit(userID, subredditID string) error {
+	return redditError(r.engine.LeaveSubreddit(userID, subredditID))
+}
+
+func (r *Reddit) CreatePost(authorID, subredditID, title, content string) (string, error) {
+	id, err := r.engine.CreatePost(subredditID, authorID, content)
+	if err != nil {
+		return "", redditError(err)
+	}
+	return fmt.Sprintf("post_%d", id), nil
+}
+
+func (r *Reddit) GetPost(postID string) (engine.Post, error) {
+	id, err := parseID("post", postID)
+	if err != nil {
+		return engine.Post{}, err
+	}
+	post, err := r.engine.GetPost(id)
+	if err != nil {
+		return engine.Post{}, redditError(err)
+	}
+	return redditPost(post), nil
+}
+
+func (r *Reddit) CreateComment(authorID, postID, parentID, content string) (string, error) {
+	post, err := parseID("post", postID)
+	if err != nil {
+		return "", err
+	}
+	parent := 0
+	if parentID != "" {
+		if parent, err = parseID("comment", parentID); err != nil {
+			return "", err
+		}
+	}
+
+	id, err := r.engine.CreateComment(post, parent, authorID, content)
+	if err != nil {
+		return "", redditError(err)
+	}
+	return fmt.Sprintf("comment_%d", id), nil
+}
+
+func (r *Reddit) GetComments(postID string) ([]engine.Comment, error) {
+	id, err := parseID("post", postID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := r.engine.GetComments(id)
+	if err != nil {
+		return nil, redditError(err)
+	}
+	return redditComments(comments, ""), nil
+}
+
+func (r *Reddit) Vote(userID, itemID string, upvote bool) error {
+	vote := -1
+	if upvote {
+		vote = 1
+	}
+
+	var itemType string
+	var id int
+	if _, err := fmt.Sscanf(itemID, "post_%d", &id); err == nil {
+		itemType = "post"
+	} else if _, err := fmt.Sscanf(itemID, "comment_%d", &id); err == nil {
+		itemType = "comment"
+	} else {
+		return notFound("item", itemID)
+	}
+	return redditError(r.engine.Vote(userID, itemType, id, vote))
+}
+
+func (r *Reddit) SendDirectMessage(fromID, toID, content string) (string, error) {
+	id, err := r.engine.SendDM(fromID, toID, content)
+	if err != nil {
+		return "", redditError(err)
+	}
+	return fmt.Sprintf("dm_%d", id), nil
+}
+
+// GetDirectMessages keeps only the received messages; the engine also
+// returns the ones the user sent.
+func (r *Reddit) GetDirectMessages(userID string) ([]engine.DirectMessage, error) {
+	var inbox []engine.DirectMessage
+	for _, dm := range r.engine.GetDMs(userID) {
+		if dm.To != userID {
+			continue
+		}
+		inbox = append(inbox, engine.DirectMessage{
+			ID:        fmt.Sprintf("dm_%d", dm.ID),
+			FromID:    dm.From,
+			ToID:      dm.To,
+			Content:   dm.Content,
+			CreatedAt: dm.CreatedAt,
+		})
+	}
+	return inbox, nil
+}
+
+func (r *Reddit) GetFeed(userID string) ([]engine.Post, error) {
+	var feed []engine.Post
+	for _, post := range r.engine.GetFeed(userID) {
+		feed = append(feed, redditPost(post))
+	}
+	return feed, nil
+}
+
+func redditError(err error) error {
+	return adaptError(err, reddit.ErrNotFound, reddit.ErrAlreadyExists)
+}
+
+func redditPost(post reddit.Post) engine.Post {
+	return engine.Post{
+		ID:          fmt.Sprintf("post_%d", post.ID),
+		AuthorID:    post.Author,
+		SubredditID: post.SubredditName,
+		Content:     post.Content,
+		Score:       post.Score,
+		CreatedAt:   post.CreatedAt,
+	}
+}
+
+func redditComments(comments []*reddit.Comment, parentID string) []engine.Comment {
+	converted := make([]engine.Comment, 0, len(comments))
+	for _, comment := range comments {
+		id := fmt.Sprintf("comment_%d", comment.ID)
+		converted = append(converted, engine.Comment{
+			ID:        id,
+			PostID:    fmt.Sprintf("post_%d", comment.PostID),
+			ParentID:  parentID,
+			AuthorID:  comment.Author,
+			Content:   comment.Content,
+			Score:     comment.Score,
+			Replies:   redditComments(comment.Replies, id),
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+	return converted
+}
+
+// parseID reads the number from a "<kind>_<n>" ID. IDs that don't parse
+// can't exist, so they are reported as not found.
+func parseID(kind, id string) (int, error) {
+	var n int
+	if _, err := fmt.Sscanf(id, kind+"_%d", &n); err != nil {
+		return 0, notFound(kind, id)
+	}
+	return n, nil
+}
+
+func notFound(kind, id string) error {
+	return fmt.Errorf("%s %q %w", kind, id, engine.ErrNotFound)
+}
@@ -0,0 +1,207 @@
+package engines
+
+import (
+	"time"
+
+	"github.com/asynkron/protoactor-go/actor"
+	"reddit_clone/redditactor"
+	"redditclone/pkg/engine"
+)
+
+// RedditActor adapts redditactor.RedditActor, running it in its own actor
+// system. Subreddit names are used as subreddit IDs.
+type RedditActor struct {
+	system  *actor.ActorSystem
+	pid     *actor.PID
+	timeout time.Duration
+}
+
+var _ engine.Engine = (*RedditActor)(nil)
+
+func NewRedditActor() *RedditActor {
+	system := actor.NewActorSystem()
+	return &RedditActor{
+		system:  system,
+		pid:     system.Root.Spawn(actor.PropsFromProducer(redditactor.NewRedditActor)),
+		timeout: 5 * time.Second,
+	}
+}
+
+func (r *RedditActor) Close() {
+	r.system.Shutdown()
+}
+
+func (r *RedditActor) RegisterUser(username string) (string, error) {
+	result, err := r.request(&redditactor.RegisterUserMsg{Username: username})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+func (r *RedditActor) GetUser(userID string) (engine.User, error) {
+	result, err := r.request(&redditactor.GetUserMsg{UserID: userID})
+	if err != nil {
+		return engine.User{}, err
+	}
+	user := result.(redditactor.User)
+	return engine.User{ID: user.ID, Username: user.Username, Karma: user.Karma}, nil
+}
+
+func (r *RedditActor) CreateSubreddit(creatorID, name, description string) (string, error) {
+	if _, err := r.request(&redditactor.CreateSubredditMsg{Name: name, Description: description}); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (r *RedditActor) JoinSubreddit(userID, subredditID string) error {
+	_, err := r.request(&redditactor.JoinSubredditMsg{UserID: userID, SubredditName: subredditID})
+	return err
+}
+
+func (r *RedditActor) LeaveSubreddit(userID, subredditID string) error {
+	_, err := r.request(&redditactor.LeaveSubredditMsg{UserID: userID, SubredditName: subredditID})
+	return err
+}
+
+func (r *RedditActor) CreatePost(authorID, subredditID, title, content string) (string, error) {
+	result, err := r.request(&redditactor.CreatePostMsg{
+		UserID:        authorID,
+		SubredditName: subredditID,
+		Title:         title,
+		Content:       content,
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+func (r *RedditActor) GetPost(postID string) (engine.Post, error) {
+	result, err := r.request(&redditactor.GetPostMsg{PostID: postID})
+	if err != nil {
+		return engine.Post{}, err
+	}
+	return redditActorPost(result.(*redditactor.Post)), nil
+}
+
+// CreateComment addresses top-level comments to the post itself, which is
+// how the actor tells them apart from replies.
+func (r *RedditActor) CreateComment(authorID, postID, parentID, content string) (string, error) {
+	if parentID == "" {
+		parentID = postID
+	}
+	result, err := r.request(&redditactor.CreateCommentMsg{
+		UserID:   authorID,
+		PostID:   postID,
+		ParentID: parentID,
+		Content:  content,
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+func (r *RedditActor) GetComments(postID string) ([]engine.Comment, error) {
+	result, err := r.request(&redditactor.GetCommentsMsg{PostID: postID})
+	if err != nil {
+		return nil, err
+	}
+	return redditActorComments(result.([]*redditactor.Comment), postID, ""), nil
+}
+
+func (r *RedditActor) Vote(userID, itemID string, upvote bool) error {
+	_, err := r.request(&redditactor.VoteMsg{UserID: userID, TargetID: itemID, IsUpvote: upvote})
+	return err
+}
+
+func (r *RedditActor) SendDirectMessage(fromID, toID, content string) (string, error) {
+	result, err := r.request(&redditactor.SendDirectMessageMsg{FromID: fromID, ToID: toID, Content: content})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// GetDirectMessages keeps only the received messages; the actor also
+// returns the ones the user sent.
+func (r *RedditActor) GetDirectMessages(userID string) ([]engine.DirectMessage, error) {
+	result, err := r.request(&redditactor.GetDirectMessagesMsg{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	var inbox []engine.DirectMessage
+	for _, dm := range result.([]*redditactor.DirectMessage) {
+		if dm.ToID != userID {
+			continue
+		}
+		inbox = append(inbox, engine.DirectMessage{
+			ID:        dm.ID,
+			FromID:    dm.FromID,
+			ToID:      dm.ToID,
+			Content:   dm.Content,
+			CreatedAt: dm.CreatedAt,
+		})
+	}
+	return inbox, nil
+}
+
+func (r *RedditActor) GetFeed(userID string) ([]engine.Post, error) {
+	result, err := r.request(&redditactor.GetFeedMsg{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	var feed []engine.Post
+	for _, post := range result.([]*redditactor.Post) {
+		feed = append(feed, redditActorPost(post))
+	}
+	return feed, nil
+}
+
+// request sends msg to the actor and returns its response, which is an
+// error when the operation failed.
+func (r *RedditActor) request(msg interface{}) (interface{}, error) {
+	result, err := r.system.Root.RequestFuture(r.pid, msg, r.timeout).Result()
+	if err != nil {
+		return nil, err
+	}
+	if err, ok := result.(error); ok {
+		return nil, adaptError(err, redditactor.ErrNotFound, redditactor.ErrAlreadyExists)
+	}
+	return result, nil
+}
+
+func redditActorPost(post *redditactor.Post) engine.Post {
+	return engine.Post{
+		ID:          post.ID,
+		AuthorID:    post.AuthorID,
+		SubredditID: post.SubredditName,
+		Title:       post.Title,
+		Content:     post.Content,
+		Score:       len(post.Upvotes) - len(post.Downvotes),
+		CreatedAt:   post.CreatedAt,
+	}
+}
+
+// The actor stores the post as the parent of top-level comments; the
+// interface leaves it empty.
+func redditActorComments(comments []*redditactor.Comment, postID, parentID string) []engine.Comment {
+	converted := make([]engine.Comment, 0, len(comments))
+	for _, comment := range comments {
+		converted = append(converted, engine.Comment{
+			ID:        comment.ID,
+			PostID:    postID,
+			ParentID:  parentID,
+			AuthorID:  comment.AuthorID,
+			Content:   comment.Content,
+			Score:     len(comment.Upvotes) - len(comment.Downvotes),
+			Replies:   redditActorComments(comment.Replies(), postID, comment.ID),
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,541 @@
+package reddit
+
+import (
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// Actors
+//
+// Every actor goroutine exclusively owns its slice of the state: the user
+// actor owns users, the subreddit actor owns subreddits and memberships, each
+// post actor owns the posts and comments of the subreddits hashed to it, and
+// the DM actor owns direct messages. Nothing is shared, so there is no lock;
+// when one domain needs another it sends it a message.
+type RedditEngine struct {
+	// Channels for different message types
+	userChan      chan Message
+	subredditChan chan Message
+	postChans     []chan Message
+	dmChan        chan Message
+
+	stopper
+}
+
+// NewRedditEngine starts an engine with one post actor per CPU.
+func NewRedditEngine() *RedditEngine {
+	return NewShardedRedditEngine(runtime.GOMAXPROCS(0))
+}
+
+// NewShardedRedditEngine starts an engine whose posts are spread across the
+// given number of post actors.
+func NewShardedRedditEngine(shards int) *RedditEngine {
+	if shards < 1 {
+		shards = 1
+	}
+
+	engine := &RedditEngine{
+		userChan:      make(chan Message, 100),
+		subredditChan: make(chan Message, 100),
+		postChans:     make([]chan Message, shards),
+		dmChan:        make(chan Message, 100),
+		stopper:       newStopper(),
+	}
+
+	// Start actor routines
+	go engine.userActor()
+	go engine.subredditActor()
+	for shard := range engine.postChans {
+		engine.postChans[shard] = make(chan Message, 100)
+		go engine.postActor(shard)
+	}
+	go engine.dmActor()
+
+	return engine
+}
+
+// Close stops every actor. Operations in flight, and any made afterwards,
+// return ErrClosed or nothing.
+func (re *RedditEngine) Close() {
+	re.stop()
+}
+
+func (re *RedditEngine) userActor() {
+	users := make(map[string]*User)
+
+	for {
+		msg, ok := re.next(re.userChan)
+		if !ok {
+			return
+		}
+		switch m := msg.(type) {
+		case RegisterUserMsg:
+			if _, exists := users[m.Username]; exists {
+				m.Reply <- fmt.Errorf("username %w", ErrAlreadyExists)
+				continue
+			}
+
+			users[m.Username] = &User{
+				Username: m.Username,
+				JoinedAt: time.Now(),
+			}
+			m.Reply <- nil
+
+		case AdjustKarmaMsg:
+			if user, exists := users[m.Username]; exists {
+				user.Karma += m.Delta
+			}
+
+		case GetUserMsg:
+			if user, exists := users[m.Username]; exists {
+				m.Reply <- UserReply{User: *user}
+			} else {
+				m.Reply <- UserReply{Err: fmt.Errorf("user %w", ErrNotFound)}
+			}
+		}
+	}
+}
+
+func (re *RedditEngine) subredditActor() {
+	subreddits := make(map[string]*Subreddit)
+
+	for {
+		msg, ok := re.next(re.subredditChan)
+		if !ok {
+			return
+		}
+		switch m := msg.(type) {
+		case CreateSubredditMsg:
+			if _, exists := subreddits[m.Name]; exists {
+				m.Reply <- fmt.Errorf("subreddit %w", ErrAlreadyExists)
+				continue
+			}
+
+			subreddits[m.Name] = &Subreddit{
+				Name:        m.Name,
+				Description: m.Description,
+				Members:     make(map[string]bool),
+				CreatedAt:   time.Now(),
+			}
+			m.Reply <- nil
+
+		case JoinSubredditMsg:
+			if subreddit, exists := subreddits[m.SubredditName]; exists {
+				subreddit.Members[m.Username] = true
+				m.Reply <- nil
+			} else {
+				m.Reply <- fmt.Errorf("subreddit %w", ErrNotFound)
+			}
+
+		case LeaveSubredditMsg:
+			if subreddit, exists := subreddits[m.SubredditName]; exists {
+				delete(subreddit.Members, m.Username)
+				m.Reply <- nil
+			} else {
+				m.Reply <- fmt.Errorf("subreddit %w", ErrNotFound)
+			}
+
+		case GetSubscriptionsMsg:
+			var names []string
+			for name, subreddit := range subreddits {
+				if subreddit.Members[m.Username] {
+					names = append(names, name)
+				}
+			}
+			m.Reply <- names
+
+		case CreatePostMsg:
+			// The subreddit actor only validates; the post actor that owns
+			// the subreddit stores the post and replies to the caller.
+			if _, exists := subreddits[m.SubredditName]; !exists {
+				m.Reply <- IDReply{Err: fmt.Errorf("subreddit %w", ErrNotFound)}
+				continue
+			}
+			if !re.send(re.postChans[re.shardForSubreddit(m.SubredditName)], m) {
+				return
+			}
+		}
+	}
+}
+
+func (re *RedditEngine) postActor(shard int) {
+	posts := make(map[int]*Post)
+	comments := make(map[int]*Comment)
+	votes := make(map[int]map[string]int) // item ID -> voter -> vote
+	nextID := 0
+
+	// IDs are interleaved across shards so the owning shard can be recovered
+	// from the ID alone.
+	newID := func() int {
+		id := nextID*len(re.postChans) + shard + 1
+		nextID++
+		return id
+	}
+
+	for {
+		msg, ok := re.next(re.postChans[shard])
+		if !ok {
+			return
+		}
+		switch m := msg.(type) {
+		case CreatePostMsg:
+			post := &Post{
+				ID:            newID(),
+				Author:        m.Author,
+				SubredditName: m.SubredditName,
+				Content:       m.Content,
+				CreatedAt:     time.Now(),
+			}
+			posts[post.ID] = post
+			m.Reply <- IDReply{ID: post.ID}
+
+		case CreateCommentMsg:
+			post, exists := posts[m.PostID]
+			if !exists {
+				m.Reply <- IDReply{Err: fmt.Errorf("post %w", ErrNotFound)}
+				continue
+			}
+
+			var parent *Comment
+			if m.ParentID != 0 {
+				parent, exists = comments[m.ParentID]
+				if !exists || parent.PostID != post.ID {
+					m.Reply <- IDReply{Err: fmt.Errorf("parent comment %w", ErrNotFound)}
+					continue
+				}
+			}
+
+			comment := &Comment{
+				ID:        newID(),
+				PostID:    post.ID,
+				Author:    m.Author,
+				Content:   m.Content,
+				CreatedAt: time.Now(),
+			}
+			comments[comment.ID] = comment
+
+			if parent == nil {
+				post.Comments = append(post.Comments, comment)
+			} else {
+				parent.Replies = append(parent.Replies, comment)
+			}
+			m.Reply <- IDReply{ID: comment.ID}
+
+		case VoteMsg:
+			var score *int
+			var author string
+			switch m.ItemType {
+			case "post":
+				post, exists := posts[m.ItemID]
+				if !exists {
+					m.Reply <- fmt.Errorf("post %w", ErrNotFound)
+					continue
+				}
+				score, author = &post.Score, post.Author
+			case "comment":
+				comment, exists := comments[m.ItemID]
+				if !exists {
+					m.Reply <- fmt.Errorf("comment %w", ErrNotFound)
+					continue
+				}
+				score, author = &comment.Score, comment.Author
+			default:
+				m.Reply <- fmt.Errorf("unknown item type %q", m.ItemType)
+				continue
+			}
+
+			// A user's later vote replaces their earlier one
+			if votes[m.ItemID] == nil {
+				votes[m.ItemID] = make(map[string]int)
+			}
+			delta := m.Vote - votes[m.ItemID][m.Username]
+			votes[m.ItemID][m.Username] = m.Vote
+			*score += delta
+
+			// Karma belongs to the user actor. The update is queued before
+			// the reply, so a caller reading karma afterwards sees it.
+			if delta != 0 && !re.send(re.userChan, AdjustKarmaMsg{Username: author, Delta: delta}) {
+				return
+			}
+			m.Reply <- nil
+
+		case GetPostsMsg:
+			if m.Subreddits == nil {
+				if post, exists := posts[m.PostID]; exists {
+					m.Reply <- PostsReply{Posts: []Post{copyPost(post)}}
+				} else {
+					m.Reply <- PostsReply{Err: fmt.Errorf("post %w", ErrNotFound)}
+				}
+				continue
+			}
+
+			var found []Post
+			for _, post := range posts {
+				if m.Subreddits[post.SubredditName] {
+					found = append(found, copyPost(post))
+				}
+			}
+			m.Reply <- PostsReply{Posts: found}
+
+		case GetCommentsMsg:
+			post, exists := posts[m.PostID]
+			if !exists {
+				m.Reply <- CommentsReply{Err: fmt.Errorf("post %w", ErrNotFound)}
+				continue
+			}
+			m.Reply <- CommentsReply{Comments: copyComments(post.Comments)}
+		}
+	}
+}
+
+func (re *RedditEngine) dmActor() {
+	directMessages := make(map[string][]*DirectMessage)
+	nextDMID := 0
+
+	for {
+		msg, ok := re.next(re.dmChan)
+		if !ok {
+			return
+		}
+		switch m := msg.(type) {
+		case SendDMMsg:
+			dm := &DirectMessage{
+				ID:        nextDMID,
+				From:      m.From,
+				To:        m.To,
+				Content:   m.Content,
+				CreatedAt: time.Now(),
+			}
+			nextDMID++
+
+			// Store DM for both sender and receiver
+			directMessages[m.From] = append(directMessages[m.From], dm)
+			if m.To != m.From {
+				directMessages[m.To] = append(directMessages[m.To], dm)
+			}
+			m.Reply <- IDReply{ID: dm.ID}
+
+		case GetDMsMsg:
+			dms := make([]DirectMessage, 0, len(directMessages[m.Username]))
+			for _, dm := range directMessages[m.Username] {
+				dms = append(dms, *dm)
+			}
+			m.Reply <- dms
+		}
+	}
+}
+
+// Posts are placed by subreddit so a subreddit's posts live on one shard.
+func (re *RedditEngine) shardForSubreddit(name string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return int(h.Sum32() % uint32(len(re.postChans)))
+}
+
+// Posts and comments are found by the shard encoded in their ID.
+func (re *RedditEngine) shardForID(id int) (int, bool) {
+	if id < 1 {
+		return 0, false
+	}
+	return (id - 1) % len(re.postChans), true
+}
+
+// copyPost returns a post without its comments, safe to hand out of the
+// actor.
+func copyPost(post *Post) Post {
+	c := *post
+	c.Comments = nil
+	return c
+}
+
+func copyComments(comments []*Comment) []*Comment {
+	copied := make([]*Comment, 0, len(comments))
+	for _, comment := range comments {
+		c := *comment
+		c.Replies = copyComments(comment.Replies)
+		copied = append(copied, &c)
+	}
+	return copied
+}
+
+// Public API methods
+func (re *RedditEngine) RegisterUser(username string) error {
+	reply := make(chan error, 1)
+	err, ok := call(&re.stopper, re.userChan, RegisterUserMsg{Username: username, Reply: reply}, reply)
+	if !ok {
+		return ErrClosed
+	}
+	return err
+}
+
+func (re *RedditEngine) GetUser(username string) (User, error) {
+	reply := make(chan UserReply, 1)
+	r, ok := call(&re.stopper, re.userChan, GetUserMsg{Username: username, Reply: reply}, reply)
+	if !ok {
+		return User{}, ErrClosed
+	}
+	return r.User, r.Err
+}
+
+func (re *RedditEngine) CreateSubreddit(name, description, creator string) error {
+	reply := make(chan error, 1)
+	err, ok := call(&re.stopper, re.subredditChan, CreateSubredditMsg{Name: name, Description: description, Creator: creator, Reply: reply}, reply)
+	if !ok {
+		return ErrClosed
+	}
+	return err
+}
+
+func (re *RedditEngine) JoinSubreddit(username, subredditName string) error {
+	reply := make(chan error, 1)
+	err, ok := call(&re.stopper, re.subredditChan, JoinSubredditMsg{Username: username, SubredditName: subredditName, Reply: reply}, reply)
+	if !ok {
+		return ErrClosed
+	}
+	return err
+}
+
+func (re *RedditEngine) LeaveSubreddit(username, subredditName string) error {
+	reply := make(chan error, 1)
+	err, ok := call(&re.stopper, re.subredditChan, LeaveSubredditMsg{Username: username, SubredditName: subredditName, Reply: reply}, reply)
+	if !ok {
+		return ErrClosed
+	}
+	return err
+}
+
+func (re *RedditEngine) CreatePost(subredditName, author, content string) (int, error) {
+	reply := make(chan IDReply, 1)
+	r, ok := call(&re.stopper, re.subredditChan, CreatePostMsg{SubredditName: subredditName, Author: author, Content: content, Reply: reply}, reply)
+	if !ok {
+		return 0, ErrClosed
+	}
+	return r.ID, r.Err
+}
+
+func (re *RedditEngine) GetPost(postID int) (Post, error) {
+	shard, ok := re.shardForID(postID)
+	if !ok {
+		return Post{}, fmt.Errorf("post %w", ErrNotFound)
+	}
+
+	reply := make(chan PostsReply, 1)
+	r, ok := call(&re.stopper, re.postChans[shard], GetPostsMsg{PostID: postID, Reply: reply}, reply)
+	if !ok {
+		return Post{}, ErrClosed
+	}
+	if r.Err != nil {
+		return Post{}, r.Err
+	}
+	return r.Posts[0], nil
+}
+
+func (re *RedditEngine) CreateComment(postID, parentID int, author, content string) (int, error) {
+	shard, ok := re.shardForID(postID)
+	if !ok {
+		return 0, fmt.Errorf("post %w", ErrNotFound)
+	}
+
+	reply := make(chan IDReply, 1)
+	r, ok := call(&re.stopper, re.postChans[shard], CreateCommentMsg{PostID: postID, ParentID: parentID, Author: author, Content: content, Reply: reply}, reply)
+	if !ok {
+		return 0, ErrClosed
+	}
+	return r.ID, r.Err
+}
+
+// GetComments returns a copy of a post's comment tree, oldest first.
+func (re *RedditEngine) GetComments(postID int) ([]*Comment, error) {
+	shard, ok := re.shardForID(postID)
+	if !ok {
+		return nil, fmt.Errorf("post %w", ErrNotFound)
+	}
+
+	reply := make(chan CommentsReply, 1)
+	r, ok := call(&re.stopper, re.postChans[shard], GetCommentsMsg{PostID: postID, Reply: reply}, reply)
+	if !ok {
+		return nil, ErrClosed
+	}
+	return r.Comments, r.Err
+}
+
+// Vote records username's vote of 1 or -1 on a post or comment, replacing
+// any earlier vote by the same user.
+func (re *RedditEngine) Vote(username, itemType string, itemID int, vote int) error {
+	shard, ok := re.shardForID(itemID)
+	if !ok {
+		return fmt.Errorf("%s %w", itemType, ErrNotFound)
+	}
+	if vote != 1 && vote != -1 {
+		return fmt.Errorf("vote must be 1 or -1, got %d", vote)
+	}
+
+	reply := make(chan error, 1)
+	err, ok := call(&re.stopper, re.postChans[shard], VoteMsg{Username: username, ItemType: itemType, ItemID: itemID, Vote: vote, Reply: reply}, reply)
+	if !ok {
+		return ErrClosed
+	}
+	return err
+}
+
+func (re *RedditEngine) SendDM(from, to, content string) (int, error) {
+	reply := make(chan IDReply, 1)
+	r, ok := call(&re.stopper, re.dmChan, SendDMMsg{From: from, To: to, Content: content, Reply: reply}, reply)
+	if !ok {
+		return 0, ErrClosed
+	}
+	return r.ID, r.Err
+}
+
+// GetDMs returns the DMs a user sent or received, oldest first, or none once
+// the engine is closed.
+func (re *RedditEngine) GetDMs(username string) []DirectMessage {
+	reply := make(chan []DirectMessage, 1)
+	dms, _ := call(&re.stopper, re.dmChan, GetDMsMsg{Username: username, Reply: reply}, reply)
+	return dms
+}
+
+// GetFeed returns the posts of the subreddits a user belongs to, highest
+// score first, or none once the engine is closed. The subreddit actor
+// supplies the memberships and every post actor is asked for its posts in
+// those subreddits.
+func (re *RedditEngine) GetFeed(username string) []Post {
+	subscriptions := make(chan []string, 1)
+	names, _ := call(&re.stopper, re.subredditChan, GetSubscriptionsMsg{Username: username, Reply: subscriptions}, subscriptions)
+	if len(names) == 0 {
+		return nil
+	}
+
+	subreddits := make(map[string]bool, len(names))
+	for _, name := range names {
+		subreddits[name] = true
+	}
+
+	reply := make(chan PostsReply, len(re.postChans))
+	for _, ch := range re.postChans {
+		if !re.send(ch, GetPostsMsg{Subreddits: subreddits, Reply: reply}) {
+			return nil
+		}
+	}
+
+	var feed []Post
+	for range re.postChans {
+		select {
+		case r := <-reply:
+			feed = append(feed, r.Posts...)
+		case <-re.done:
+			return nil
+		}
+	}
+
+	sort.Slice(feed, func(i, j int) bool {
+		if feed[i].Score != feed[j].Score {
+			return feed[i].Score > feed[j].Score
+		}
+		return feed[i].CreatedAt.After(feed[j].CreatedAt)
+	})
+	return feed
+}
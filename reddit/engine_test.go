@@ -0,0 +1,183 @@
+package reddit
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// benchEngine is what the benchmarks need of RedditEngine and LockedEngine.
+type benchEngine interface {
+	RegisterUser(username string) error
+	CreateSubreddit(name, description, creator string) error
+	JoinSubreddit(username, subredditName string) error
+	CreatePost(subredditName, author, content string) (int, error)
+	CreateComment(postID, parentID int, author, content string) (int, error)
+	Vote(username, itemType string, itemID int, vote int) error
+	Close()
+}
+
+const (
+	benchSubreddits = 64
+	benchPosts      = 1024
+)
+
+// The benchmarks compare the per-domain RedditEngine, with one to
+// GOMAXPROCS post shards, against the original single-mutex LockedEngine:
+//
+//	go test ./reddit -bench . -cpu 8
+func BenchmarkCreatePost(b *testing.B)    { benchBackends(b, benchCreatePost) }
+func BenchmarkCreateComment(b *testing.B) { benchBackends(b, benchCreateComment) }
+func BenchmarkVote(b *testing.B)          { benchBackends(b, benchVote) }
+
+// BenchmarkMixed issues 20% posts, 30% comments and 50% votes.
+func BenchmarkMixed(b *testing.B) { benchBackends(b, benchMixed) }
+
+func benchBackends(b *testing.B, run func(*testing.B, benchEngine, []string, []int)) {
+	backends := []struct {
+		name string
+		new  func() benchEngine
+	}{
+		{"locked", func() benchEngine { return NewLockedEngine() }},
+	}
+	for shards := 1; shards <= runtime.GOMAXPROCS(0); shards *= 2 {
+		shards := shards
+		backends = append(backends, struct {
+			name string
+			new  func() benchEngine
+		}{fmt.Sprintf("sharded-%d", shards), func() benchEngine { return NewShardedRedditEngine(shards) }})
+	}
+
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			e := backend.new()
+			defer e.Close()
+			subreddits, posts := seed(e, benchSubreddits, benchPosts)
+			b.ResetTimer()
+			run(b, e, subreddits, posts)
+		})
+	}
+}
+
+// seed registers a user, creates the subreddits and spreads the posts
+// across them.
+func seed(e benchEngine, numSubreddits, numPosts int) ([]string, []int) {
+	e.RegisterUser("bench")
+
+	subreddits := make([]string, numSubreddits)
+	for i := range subreddits {
+		subreddits[i] = fmt.Sprintf("subreddit_%d", i)
+		e.CreateSubreddit(subreddits[i], "", "bench")
+		e.JoinSubreddit("bench", subreddits[i])
+	}
+
+	posts := make([]int, 0, numPosts)
+	for i := 0; i < numPosts; i++ {
+		id, err := e.CreatePost(subreddits[i%numSubreddits], "bench", "seed")
+		if err == nil {
+			posts = append(posts, id)
+		}
+	}
+	return subreddits, posts
+}
+
+func benchCreatePost(b *testing.B, e benchEngine, subreddits []string, posts []int) {
+	var next atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			i := int(next.Add(1))
+			e.CreatePost(subreddits[i%len(subreddits)], "bench", "content")
+		}
+	})
+}
+
+func benchCreateComment(b *testing.B, e benchEngine, subreddits []string, posts []int) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			e.CreateComment(posts[rand.IntN(len(posts))], 0, "bench", "comment")
+		}
+	})
+}
+
+func benchVote(b *testing.B, e benchEngine, subreddits []string, posts []int) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			e.Vote(voter(), "post", posts[rand.IntN(len(posts))], vote())
+		}
+	})
+}
+
+func benchMixed(b *testing.B, e benchEngine, subreddits []string, posts []int) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			switch n := rand.IntN(10); {
+			case n < 2:
+				e.CreatePost(subreddits[rand.IntN(len(subreddits))], "bench", "content")
+			case n < 5:
+				e.CreateComment(posts[rand.IntN(len(posts))], 0, "bench", "comment")
+			default:
+				e.Vote(voter(), "post", posts[rand.IntN(len(posts))], vote())
+			}
+		}
+	})
+}
+
+var voters = func() []string {
+	names := make([]string, 1000)
+	for i := range names {
+		names[i] = fmt.Sprintf("voter_%d", i)
+	}
+	return names
+}()
+
+func voter() string {
+	return voters[rand.IntN(len(voters))]
+}
+
+// vote upvotes 70% of the time, like the simulator.
+func vote() int {
+	if rand.IntN(10) < 7 {
+		return 1
+	}
+	return -1
+}
+
+// TestCloseWithOperationsInFlight closes engines while goroutines are still
+// calling them, which used to panic with a send on a closed channel. Every
+// call must return, with ErrClosed once the engine is closed.
+func TestCloseWithOperationsInFlight(t *testing.T) {
+	for _, e := range []benchEngine{NewShardedRedditEngine(4), NewLockedEngine()} {
+		subreddits, posts := seed(e, 8, 64)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					_, err := e.CreatePost(subreddits[j%len(subreddits)], "bench", "content")
+					if verr := e.Vote(voter(), "post", posts[j%len(posts)], vote()); err == nil {
+						err = verr
+					}
+					if errors.Is(err, ErrClosed) {
+						return
+					}
+				}
+			}()
+		}
+		e.Close()
+		e.Close()
+		wg.Wait()
+
+		if _, err := e.CreatePost(subreddits[0], "bench", "content"); !errors.Is(err, ErrClosed) {
+			t.Errorf("%T: CreatePost after Close = %v, want ErrClosed", e, err)
+		}
+		if err := e.RegisterUser("late"); !errors.Is(err, ErrClosed) {
+			t.Errorf("%T: RegisterUser after Close = %v, want ErrClosed", e, err)
+		}
+	}
+}
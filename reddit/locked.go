@@ -0,0 +1,330 @@
+package reddit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LockedEngine is the original engine design: the same four actor
+// goroutines, but all of them share one state behind a single mutex, so
+// they run one at a time. It is kept as the baseline for the
+// benchmarks in engine_test.go.
+type LockedEngine struct {
+	users          map[string]*User
+	subreddits     map[string]*Subreddit
+	posts          map[string][]*Post
+	directMessages map[string][]*DirectMessage
+	nextPostID     int
+	nextCommentID  int
+	nextDMID       int
+	mu             sync.RWMutex
+
+	userChan      chan Message
+	subredditChan chan Message
+	postChan      chan Message
+	dmChan        chan Message
+
+	stopper
+}
+
+func NewLockedEngine() *LockedEngine {
+	engine := &LockedEngine{
+		users:          make(map[string]*User),
+		subreddits:     make(map[string]*Subreddit),
+		posts:          make(map[string][]*Post),
+		directMessages: make(map[string][]*DirectMessage),
+		userChan:       make(chan Message, 100),
+		subredditChan:  make(chan Message, 100),
+		postChan:       make(chan Message, 100),
+		dmChan:         make(chan Message, 100),
+		stopper:        newStopper(),
+	}
+
+	go engine.userActor()
+	go engine.subredditActor()
+	go engine.postActor()
+	go engine.dmActor()
+
+	return engine
+}
+
+// Close stops every actor. Operations in flight, and any made afterwards,
+// return ErrClosed.
+func (le *LockedEngine) Close() {
+	le.stop()
+}
+
+func (le *LockedEngine) userActor() {
+	for {
+		msg, ok := le.next(le.userChan)
+		if !ok {
+			return
+		}
+		switch m := msg.(type) {
+		case RegisterUserMsg:
+			le.mu.Lock()
+			if _, exists := le.users[m.Username]; exists {
+				le.mu.Unlock()
+				m.Reply <- fmt.Errorf("username already taken")
+				continue
+			}
+
+			le.users[m.Username] = &User{
+				Username: m.Username,
+				JoinedAt: time.Now(),
+			}
+			le.mu.Unlock()
+			m.Reply <- nil
+		}
+	}
+}
+
+func (le *LockedEngine) subredditActor() {
+	for {
+		msg, ok := le.next(le.subredditChan)
+		if !ok {
+			return
+		}
+		switch m := msg.(type) {
+		case CreateSubredditMsg:
+			le.mu.Lock()
+			if _, exists := le.subreddits[m.Name]; exists {
+				le.mu.Unlock()
+				m.Reply <- fmt.Errorf("subreddit already exists")
+				continue
+			}
+
+			le.subreddits[m.Name] = &Subreddit{
+				Name:        m.Name,
+				Description: m.Description,
+				Members:     make(map[string]bool),
+				CreatedAt:   time.Now(),
+			}
+			le.mu.Unlock()
+			m.Reply <- nil
+
+		case JoinSubredditMsg:
+			le.mu.Lock()
+			if subreddit, exists := le.subreddits[m.SubredditName]; exists {
+				subreddit.Members[m.Username] = true
+				le.mu.Unlock()
+				m.Reply <- nil
+			} else {
+				le.mu.Unlock()
+				m.Reply <- fmt.Errorf("subreddit not found")
+			}
+
+		case LeaveSubredditMsg:
+			le.mu.Lock()
+			if subreddit, exists := le.subreddits[m.SubredditName]; exists {
+				delete(subreddit.Members, m.Username)
+				le.mu.Unlock()
+				m.Reply <- nil
+			} else {
+				le.mu.Unlock()
+				m.Reply <- fmt.Errorf("subreddit not found")
+			}
+		}
+	}
+}
+
+func (le *LockedEngine) postActor() {
+	for {
+		msg, ok := le.next(le.postChan)
+		if !ok {
+			return
+		}
+		switch m := msg.(type) {
+		case CreatePostMsg:
+			le.mu.Lock()
+			if _, exists := le.subreddits[m.SubredditName]; exists {
+				post := &Post{
+					ID:            le.nextPostID,
+					Author:        m.Author,
+					SubredditName: m.SubredditName,
+					Content:       m.Content,
+					CreatedAt:     time.Now(),
+				}
+				le.nextPostID++
+				le.posts[m.SubredditName] = append(le.posts[m.SubredditName], post)
+				le.mu.Unlock()
+				m.Reply <- IDReply{ID: post.ID}
+			} else {
+				le.mu.Unlock()
+				m.Reply <- IDReply{Err: fmt.Errorf("subreddit not found")}
+			}
+
+		case CreateCommentMsg:
+			le.mu.Lock()
+			comment := &Comment{
+				ID:        le.nextCommentID,
+				PostID:    m.PostID,
+				Author:    m.Author,
+				Content:   m.Content,
+				CreatedAt: time.Now(),
+			}
+			le.nextCommentID++
+
+			found := false
+			for _, posts := range le.posts {
+				for _, post := range posts {
+					if post.ID == m.PostID {
+						if m.ParentID == 0 {
+							post.Comments = append(post.Comments, comment)
+							found = true
+						} else {
+							found = addReplyToComment(post.Comments, m.ParentID, comment)
+						}
+						break
+					}
+				}
+				if found {
+					break
+				}
+			}
+			le.mu.Unlock()
+
+			if !found {
+				m.Reply <- IDReply{Err: fmt.Errorf("post or parent comment not found")}
+			} else {
+				m.Reply <- IDReply{ID: comment.ID}
+			}
+
+		case VoteMsg:
+			le.mu.Lock()
+			if m.ItemType == "post" {
+				found := false
+				for _, posts := range le.posts {
+					for _, post := range posts {
+						if post.ID == m.ItemID {
+							post.Score += m.Vote
+							if author, exists := le.users[post.Author]; exists {
+								author.Karma += m.Vote
+							}
+							found = true
+							break
+						}
+					}
+					if found {
+						break
+					}
+				}
+				le.mu.Unlock()
+				if !found {
+					m.Reply <- fmt.Errorf("post not found")
+				} else {
+					m.Reply <- nil
+				}
+			} else {
+				le.mu.Unlock()
+				m.Reply <- nil
+			}
+		}
+	}
+}
+
+func (le *LockedEngine) dmActor() {
+	for {
+		msg, ok := le.next(le.dmChan)
+		if !ok {
+			return
+		}
+		switch m := msg.(type) {
+		case SendDMMsg:
+			le.mu.Lock()
+			dm := &DirectMessage{
+				ID:        le.nextDMID,
+				From:      m.From,
+				To:        m.To,
+				Content:   m.Content,
+				CreatedAt: time.Now(),
+			}
+			le.nextDMID++
+
+			le.directMessages[m.From] = append(le.directMessages[m.From], dm)
+			le.directMessages[m.To] = append(le.directMessages[m.To], dm)
+			le.mu.Unlock()
+			m.Reply <- IDReply{ID: dm.ID}
+		}
+	}
+}
+
+// Helper function to recursively add replies to comments
+func addReplyToComment(comments []*Comment, parentID int, newComment *Comment) bool {
+	for _, comment := range comments {
+		if comment.ID == parentID {
+			comment.Replies = append(comment.Replies, newComment)
+			return true
+		}
+		if found := addReplyToComment(comment.Replies, parentID, newComment); found {
+			return true
+		}
+	}
+	return false
+}
+
+func (le *LockedEngine) RegisterUser(username string) error {
+	reply := make(chan error, 1)
+	err, ok := call(&le.stopper, le.userChan, RegisterUserMsg{Username: username, Reply: reply}, reply)
+	if !ok {
+		return ErrClosed
+	}
+	return err
+}
+
+func (le *LockedEngine) CreateSubreddit(name, description, creator string) error {
+	reply := make(chan error, 1)
+	err, ok := call(&le.stopper, le.subredditChan, CreateSubredditMsg{Name: name, Description: description, Creator: creator, Reply: reply}, reply)
+	if !ok {
+		return ErrClosed
+	}
+	return err
+}
+
+func (le *LockedEngine) JoinSubreddit(username, subredditName string) error {
+	reply := make(chan error, 1)
+	err, ok := call(&le.stopper, le.subredditChan, JoinSubredditMsg{Username: username, SubredditName: subredditName, Reply: reply}, reply)
+	if !ok {
+		return ErrClosed
+	}
+	return err
+}
+
+func (le *LockedEngine) CreatePost(subredditName, author, content string) (int, error) {
+	reply := make(chan IDReply, 1)
+	r, ok := call(&le.stopper, le.postChan, CreatePostMsg{SubredditName: subredditName, Author: author, Content: content, Reply: reply}, reply)
+	if !ok {
+		return 0, ErrClosed
+	}
+	return r.ID, r.Err
+}
+
+func (le *LockedEngine) CreateComment(postID, parentID int, author, content string) (int, error) {
+	reply := make(chan IDReply, 1)
+	r, ok := call(&le.stopper, le.postChan, CreateCommentMsg{PostID: postID, ParentID: parentID, Author: author, Content: content, Reply: reply}, reply)
+	if !ok {
+		return 0, ErrClosed
+	}
+	return r.ID, r.Err
+}
+
+// Vote applies a vote to a post. The original design does not track who
+// voted, so username is ignored, and comment votes are accepted but dropped.
+func (le *LockedEngine) Vote(username, itemType string, itemID int, vote int) error {
+	reply := make(chan error, 1)
+	err, ok := call(&le.stopper, le.postChan, VoteMsg{ItemType: itemType, ItemID: itemID, Vote: vote, Reply: reply}, reply)
+	if !ok {
+		return ErrClosed
+	}
+	return err
+}
+
+func (le *LockedEngine) SendDM(from, to, content string) (int, error) {
+	reply := make(chan IDReply, 1)
+	r, ok := call(&le.stopper, le.dmChan, SendDMMsg{From: from, To: to, Content: content, Reply: reply}, reply)
+	if !ok {
+		return 0, ErrClosed
+	}
+	return r.ID, r.Err
+}
@@ -0,0 +1,293 @@
+package reddit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Errors returned by the engines, wrapped with what was missing or taken
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+
+	// ErrClosed is returned by operations on an engine that was closed
+	// before they were answered.
+	ErrClosed = errors.New("engine closed")
+)
+
+// stopper lets an engine's actors and callers give up once the engine is
+// closed. The actors' channels are never closed, so an operation in flight
+// when Close is called gets ErrClosed instead of a send on a closed
+// channel. Replies go on channels with room for them, so an actor never
+// waits on a caller that has given up.
+type stopper struct {
+	done chan struct{}
+	once sync.Once
+}
+
+func newStopper() stopper {
+	return stopper{done: make(chan struct{})}
+}
+
+func (s *stopper) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// next is an actor's next message, or false once the engine is closed.
+func (s *stopper) next(ch <-chan Message) (Message, bool) {
+	select {
+	case msg := <-ch:
+		return msg, true
+	case <-s.done:
+		return nil, false
+	}
+}
+
+// send queues msg for an actor, or reports false once the engine is closed.
+func (s *stopper) send(ch chan<- Message, msg Message) bool {
+	select {
+	case ch <- msg:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// call sends msg and waits for its reply, or reports false once the engine
+// is closed.
+func call[T any](s *stopper, ch chan<- Message, msg Message, reply <-chan T) (T, bool) {
+	var r T
+	select {
+	case <-s.done:
+		return r, false
+	default:
+	}
+	if !s.send(ch, msg) {
+		return r, false
+	}
+	select {
+	case r = <-reply:
+		return r, true
+	case <-s.done:
+		return r, false
+	}
+}
+
+// Data structures
+type User struct {
+	Username string
+	Karma    int
+	JoinedAt time.Time
+}
+
+type Post struct {
+	ID            int
+	Author        string
+	SubredditName string
+	Content       string
+	Score         int
+	Comments      []*Comment
+	CreatedAt     time.Time
+}
+
+type Comment struct {
+	ID        int
+	PostID    int
+	Author    string
+	Content   string
+	Score     int
+	Replies   []*Comment
+	CreatedAt time.Time
+}
+
+type Subreddit struct {
+	Name        string
+	Description string
+	Members     map[string]bool
+	CreatedAt   time.Time
+}
+
+type DirectMessage struct {
+	ID        int
+	From      string
+	To        string
+	Content   string
+	CreatedAt time.Time
+}
+
+// Message interface
+type Message interface {
+	MessageType() string
+}
+
+// User Management Messages
+type RegisterUserMsg struct {
+	Username string
+	Reply    chan error
+}
+
+func (m RegisterUserMsg) MessageType() string {
+	return "RegisterUser"
+}
+
+// Sent by the post actors when an item's score changes. There is no reply,
+// the user actor applies it in the order it arrives.
+type AdjustKarmaMsg struct {
+	Username string
+	Delta    int
+}
+
+func (m AdjustKarmaMsg) MessageType() string {
+	return "AdjustKarma"
+}
+
+// Subreddit Messages
+type CreateSubredditMsg struct {
+	Name        string
+	Description string
+	Creator     string
+	Reply       chan error
+}
+
+func (m CreateSubredditMsg) MessageType() string {
+	return "CreateSubreddit"
+}
+
+type JoinSubredditMsg struct {
+	Username      string
+	SubredditName string
+	Reply         chan error
+}
+
+func (m JoinSubredditMsg) MessageType() string {
+	return "JoinSubreddit"
+}
+
+type LeaveSubredditMsg struct {
+	Username      string
+	SubredditName string
+	Reply         chan error
+}
+
+func (m LeaveSubredditMsg) MessageType() string {
+	return "LeaveSubreddit"
+}
+
+// Post Messages
+type CreatePostMsg struct {
+	SubredditName string
+	Author        string
+	Content       string
+	Reply         chan IDReply
+}
+
+func (m CreatePostMsg) MessageType() string {
+	return "CreatePost"
+}
+
+// IDReply carries the ID assigned to a new post, comment or direct message.
+type IDReply struct {
+	ID  int
+	Err error
+}
+
+type CreateCommentMsg struct {
+	PostID   int
+	ParentID int
+	Author   string
+	Content  string
+	Reply    chan IDReply
+}
+
+func (m CreateCommentMsg) MessageType() string {
+	return "CreateComment"
+}
+
+type VoteMsg struct {
+	Username string
+	ItemType string // "post" or "comment"
+	ItemID   int
+	Vote     int // 1 for upvote, -1 for downvote
+	Reply    chan error
+}
+
+func (m VoteMsg) MessageType() string {
+	return "Vote"
+}
+
+// Direct Message
+type SendDMMsg struct {
+	From    string
+	To      string
+	Content string
+	Reply   chan IDReply
+}
+
+func (m SendDMMsg) MessageType() string {
+	return "SendDM"
+}
+
+// Queries. Replies carry copies, never the state an actor owns.
+type GetUserMsg struct {
+	Username string
+	Reply    chan UserReply
+}
+
+func (m GetUserMsg) MessageType() string {
+	return "GetUser"
+}
+
+type UserReply struct {
+	User User
+	Err  error
+}
+
+type GetSubscriptionsMsg struct {
+	Username string
+	Reply    chan []string
+}
+
+func (m GetSubscriptionsMsg) MessageType() string {
+	return "GetSubscriptions"
+}
+
+// Asks a post actor for a post by ID, or, when Subreddits is set, for every
+// post it holds in those subreddits.
+type GetPostsMsg struct {
+	PostID     int
+	Subreddits map[string]bool
+	Reply      chan PostsReply
+}
+
+func (m GetPostsMsg) MessageType() string {
+	return "GetPosts"
+}
+
+type PostsReply struct {
+	Posts []Post
+	Err   error
+}
+
+type GetCommentsMsg struct {
+	PostID int
+	Reply  chan CommentsReply
+}
+
+func (m GetCommentsMsg) MessageType() string {
+	return "GetComments"
+}
+
+type CommentsReply struct {
+	Comments []*Comment
+	Err      error
+}
+
+type GetDMsMsg struct {
+	Username string
+	Reply    chan []DirectMessage
+}
+
+func (m GetDMsMsg) MessageType() string {
+	return "GetDMs"
+}
@@ -1,12 +1,19 @@
-package main
+package redditactor
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"time"
 	"github.com/asynkron/protoactor-go/actor"
 )
 
+// Errors the actor responds with, wrapped with what was missing or taken
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+)
+
 // Data structures
 type User struct {
 	ID       string
@@ -42,6 +49,7 @@ type Comment struct {
 	Upvotes   map[string]bool
 	Downvotes map[string]bool
 	CreatedAt time.Time
+	seq       int
 }
 
 type DirectMessage struct {
@@ -80,8 +88,11 @@ type (
 		Content       string
 	}
 
+	// ParentID is a post or comment. When PostID is set the parent must
+	// belong to that post.
 	CreateCommentMsg struct {
 		UserID   string
+		PostID   string
 		ParentID string
 		Content  string
 	}
@@ -98,6 +109,18 @@ type (
 		Content string
 	}
 
+	GetUserMsg struct {
+		UserID string
+	}
+
+	GetPostMsg struct {
+		PostID string
+	}
+
+	GetCommentsMsg struct {
+		PostID string
+	}
+
 	GetFeedMsg struct {
 		UserID string
 	}
@@ -112,6 +135,7 @@ type RedditActor struct {
 	users      map[string]*User
 	subreddits map[string]*Subreddit
 	directMsgs map[string][]*DirectMessage
+	nextID     int
 }
 
 // Initialize Reddit Actor
@@ -126,6 +150,13 @@ func NewRedditActor() actor.Actor {
 func (state *RedditActor) Receive(context actor.Context) {
 	switch msg := context.Message().(type) {
 	case *RegisterUserMsg:
+		for _, user := range state.users {
+			if user.Username == msg.Username {
+				context.Respond(fmt.Errorf("username %w", ErrAlreadyExists))
+				return
+			}
+		}
+
 		userID := fmt.Sprintf("user_%d", len(state.users)+1)
 		user := &User{
 			ID:       userID,
@@ -137,7 +168,7 @@ func (state *RedditActor) Receive(context actor.Context) {
 
 	case *CreateSubredditMsg:
 		if _, exists := state.subreddits[msg.Name]; exists {
-			context.Respond(fmt.Errorf("subreddit already exists"))
+			context.Respond(fmt.Errorf("subreddit %w", ErrAlreadyExists))
 			return
 		}
 
@@ -153,13 +184,13 @@ func (state *RedditActor) Receive(context actor.Context) {
 	case *JoinSubredditMsg:
 		subreddit, exists := state.subreddits[msg.SubredditName]
 		if !exists {
-			context.Respond(fmt.Errorf("subreddit not found"))
+			context.Respond(fmt.Errorf("subreddit %w", ErrNotFound))
 			return
 		}
 
 		user, exists := state.users[msg.UserID]
 		if !exists {
-			context.Respond(fmt.Errorf("user not found"))
+			context.Respond(fmt.Errorf("user %w", ErrNotFound))
 			return
 		}
 
@@ -169,7 +200,7 @@ func (state *RedditActor) Receive(context actor.Context) {
 	case *LeaveSubredditMsg:
 		subreddit, exists := state.subreddits[msg.SubredditName]
 		if !exists {
-			context.Respond(fmt.Errorf("subreddit not found"))
+			context.Respond(fmt.Errorf("subreddit %w", ErrNotFound))
 			return
 		}
 
@@ -179,11 +210,11 @@ func (state *RedditActor) Receive(context actor.Context) {
 	case *CreatePostMsg:
 		subreddit, exists := state.subreddits[msg.SubredditName]
 		if !exists {
-			context.Respond(fmt.Errorf("subreddit not found"))
+			context.Respond(fmt.Errorf("subreddit %w", ErrNotFound))
 			return
 		}
 
-		postID := fmt.Sprintf("post_%d", len(subreddit.Posts)+1)
+		postID := fmt.Sprintf("post_%d", state.newID())
 		post := &Post{
 			ID:            postID,
 			AuthorID:      msg.UserID,
@@ -199,7 +230,8 @@ func (state *RedditActor) Receive(context actor.Context) {
 		context.Respond(postID)
 
 	case *CreateCommentMsg:
-		commentID := fmt.Sprintf("comment_%d", time.Now().UnixNano())
+		seq := state.newID()
+		commentID := fmt.Sprintf("comment_%d", seq)
 		comment := &Comment{
 			ID:        commentID,
 			AuthorID:  msg.UserID,
@@ -209,6 +241,7 @@ func (state *RedditActor) Receive(context actor.Context) {
 			Upvotes:   make(map[string]bool),
 			Downvotes: make(map[string]bool),
 			CreatedAt: time.Now(),
+			seq:       seq,
 		}
 
 		found := false
@@ -220,20 +253,23 @@ func (state *RedditActor) Receive(context actor.Context) {
 				break
 			}
 
-            // Check if parent is a comment using recursion
-            for _, post := range subreddit.Posts {
-                if state.addCommentToParent(post.Comments, msg.ParentID, comment) {
-                    found = true
-                    break
-                }
-            }
-            if found {
-                break
-            }
+			// Check if parent is a comment using recursion
+			for _, post := range subreddit.Posts {
+				if msg.PostID != "" && post.ID != msg.PostID {
+					continue
+				}
+				if state.addCommentToParent(post.Comments, msg.ParentID, comment) {
+					found = true
+					break
+				}
+			}
+			if found {
+				break
+			}
 		}
 
 		if !found {
-			context.Respond(fmt.Errorf("parent post or comment not found"))
+			context.Respond(fmt.Errorf("parent post or comment %w", ErrNotFound))
 			return
 		}
 		context.Respond(commentID)
@@ -243,8 +279,8 @@ func (state *RedditActor) Receive(context actor.Context) {
 		// Try to find and update the target (post or comment)
 		for _, subreddit := range state.subreddits {
 			if post, exists := subreddit.Posts[msg.TargetID]; exists {
-				state.updateVotes(post.Upvotes, post.Downvotes, msg.UserID, msg.IsUpvote)
-				state.updateUserKarma(post.AuthorID, msg.IsUpvote)
+				karma := state.updateVotes(post.Upvotes, post.Downvotes, msg.UserID, msg.IsUpvote)
+				state.updateUserKarma(post.AuthorID, karma)
 				found = true
 				break
 			}
@@ -262,14 +298,14 @@ func (state *RedditActor) Receive(context actor.Context) {
 		}
 
 		if !found {
-			context.Respond(fmt.Errorf("target not found"))
+			context.Respond(fmt.Errorf("target %w", ErrNotFound))
 			return
 		}
 		context.Respond(nil)
 
 	case *SendDirectMessageMsg:
 		dm := &DirectMessage{
-			ID:        fmt.Sprintf("dm_%d", time.Now().UnixNano()),
+			ID:        fmt.Sprintf("dm_%d", state.newID()),
 			FromID:    msg.FromID,
 			ToID:      msg.ToID,
 			Content:   msg.Content,
@@ -277,15 +313,43 @@ func (state *RedditActor) Receive(context actor.Context) {
 		}
 
 		state.directMsgs[msg.FromID] = append(state.directMsgs[msg.FromID], dm)
-		state.directMsgs[msg.ToID] = append(state.directMsgs[msg.ToID], dm)
+		if msg.ToID != msg.FromID {
+			state.directMsgs[msg.ToID] = append(state.directMsgs[msg.ToID], dm)
+		}
 		context.Respond(dm.ID)
 
+	case *GetUserMsg:
+		user, exists := state.users[msg.UserID]
+		if !exists {
+			context.Respond(fmt.Errorf("user %w", ErrNotFound))
+			return
+		}
+		context.Respond(*user)
+
+	case *GetPostMsg:
+		for _, subreddit := range state.subreddits {
+			if post, exists := subreddit.Posts[msg.PostID]; exists {
+				context.Respond(copyPost(post))
+				return
+			}
+		}
+		context.Respond(fmt.Errorf("post %w", ErrNotFound))
+
+	case *GetCommentsMsg:
+		for _, subreddit := range state.subreddits {
+			if post, exists := subreddit.Posts[msg.PostID]; exists {
+				context.Respond(copyComments(post.Comments))
+				return
+			}
+		}
+		context.Respond(fmt.Errorf("post %w", ErrNotFound))
+
 	case *GetFeedMsg:
 		var posts []*Post
 		for _, subreddit := range state.subreddits {
 			if _, isMember := subreddit.Members[msg.UserID]; isMember {
 				for _, post := range subreddit.Posts {
-					posts = append(posts, post)
+					posts = append(posts, copyPost(post))
 				}
 			}
 		}
@@ -302,15 +366,22 @@ func (state *RedditActor) Receive(context actor.Context) {
 		context.Respond(posts)
 
 	case *GetDirectMessagesMsg:
-		messages := state.directMsgs[msg.UserID]
-		sort.Slice(messages, func(i, j int) bool {
-			return messages[i].CreatedAt.After(messages[j].CreatedAt)
-		})
+		// Oldest first, in the order they were sent
+		messages := make([]*DirectMessage, 0, len(state.directMsgs[msg.UserID]))
+		for _, dm := range state.directMsgs[msg.UserID] {
+			copied := *dm
+			messages = append(messages, &copied)
+		}
 		context.Respond(messages)
 	}
 }
 
 // Helper functions
+func (state *RedditActor) newID() int {
+	state.nextID++
+	return state.nextID
+}
+
 func (state *RedditActor) addCommentToParent(comments map[string]*Comment, parentID string, newComment *Comment) bool {
 	if comment, exists := comments[parentID]; exists {
 		comment.Comments[newComment.ID] = newComment
@@ -325,7 +396,10 @@ func (state *RedditActor) addCommentToParent(comments map[string]*Comment, paren
 	return false
 }
 
-func (state *RedditActor) updateVotes(upvotes, downvotes map[string]bool, userID string, isUpvote bool) {
+// updateVotes records a user's vote and returns the change in score, so a
+// repeated vote changes nothing and a switched vote moves the score by two.
+func (state *RedditActor) updateVotes(upvotes, downvotes map[string]bool, userID string, isUpvote bool) int {
+	before := len(upvotes) - len(downvotes)
 	if isUpvote {
 		delete(downvotes, userID)
 		upvotes[userID] = true
@@ -333,22 +407,19 @@ func (state *RedditActor) updateVotes(upvotes, downvotes map[string]bool, userID
 		delete(upvotes, userID)
 		downvotes[userID] = true
 	}
+	return len(upvotes) - len(downvotes) - before
 }
 
-func (state *RedditActor) updateUserKarma(authorID string, isUpvote bool) {
+func (state *RedditActor) updateUserKarma(authorID string, delta int) {
 	if author, exists := state.users[authorID]; exists {
-		if isUpvote {
-			author.Karma++
-		} else {
-			author.Karma--
-		}
+		author.Karma += delta
 	}
 }
 
 func (state *RedditActor) findAndUpdateVote(comments map[string]*Comment, targetID, userID string, isUpvote bool) bool {
 	if comment, exists := comments[targetID]; exists {
-		state.updateVotes(comment.Upvotes, comment.Downvotes, userID, isUpvote)
-		state.updateUserKarma(comment.AuthorID, isUpvote)
+		karma := state.updateVotes(comment.Upvotes, comment.Downvotes, userID, isUpvote)
+		state.updateUserKarma(comment.AuthorID, karma)
 		return true
 	}
 
@@ -360,37 +431,52 @@ func (state *RedditActor) findAndUpdateVote(comments map[string]*Comment, target
 	return false
 }
 
-func main() {
-	system := actor.NewActorSystem()
-	props := actor.PropsFromProducer(NewRedditActor)
-	pid := system.Root.Spawn(props)
-
-	// Example usage
-	context := system.Root
-	
-	// Register a user
-	result, _ := context.RequestFuture(pid, &RegisterUserMsg{Username: "testuser"}, 5*time.Second).Result()
-	userID := result.(string)
-	
-	// Create a subreddit
-	context.RequestFuture(pid, &CreateSubredditMsg{
-		Name:        "programming",
-		Description: "Discussion about programming",
-	}, 5*time.Second).Wait()
-	
-	// Join subreddit
-	context.RequestFuture(pid, &JoinSubredditMsg{
-		UserID:        userID,
-		SubredditName: "programming",
-	}, 5*time.Second).Wait()
-	
-	// Create a post
-	postResult, _ := context.RequestFuture(pid, &CreatePostMsg{
-		UserID:        userID,
-		SubredditName: "programming",
-		Title:         "Hello Proto.Actor",
-		Content:       "This is my first post using Proto.Actor",
-	}, 5*time.Second).Result()
-	
-	fmt.Printf("Created post with ID: %s\n", postResult)
-}
\ No newline at end of file
+// copyPost returns a copy of a post safe to send out of the actor, without
+// its comments.
+func copyPost(post *Post) *Post {
+	copied := *post
+	copied.Comments = nil
+	copied.Upvotes = copyVotes(post.Upvotes)
+	copied.Downvotes = copyVotes(post.Downvotes)
+	return &copied
+}
+
+// copyComments returns deep copies of a comment tree, oldest first.
+func copyComments(comments map[string]*Comment) []*Comment {
+	copied := make(map[string]*Comment, len(comments))
+	for id, comment := range comments {
+		c := *comment
+		c.Upvotes = copyVotes(comment.Upvotes)
+		c.Downvotes = copyVotes(comment.Downvotes)
+		c.Comments = make(map[string]*Comment, len(comment.Comments))
+		for _, reply := range copyComments(comment.Comments) {
+			c.Comments[reply.ID] = reply
+		}
+		copied[id] = &c
+	}
+	return sortComments(copied)
+}
+
+func sortComments(comments map[string]*Comment) []*Comment {
+	sorted := make([]*Comment, 0, len(comments))
+	for _, comment := range comments {
+		sorted = append(sorted, comment)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].seq < sorted[j].seq
+	})
+	return sorted
+}
+
+func copyVotes(votes map[string]bool) map[string]bool {
+	copied := make(map[string]bool, len(votes))
+	for userID := range votes {
+		copied[userID] = true
+	}
+	return copied
+}
+
+// Replies returns a comment's replies oldest first.
+func (c *Comment) Replies() []*Comment {
+	return sortComments(c.Comments)
+}
@@ -3,72 +3,198 @@ package main
 
 import (
     "flag"
+    "fmt"
     "log"
-    
+    "net/http"
+    "os"
+    "os/signal"
+    "strings"
+    "syscall"
+    "time"
+
     "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/cluster"
+    "github.com/asynkron/protoactor-go/cluster/clusterproviders/automanaged"
+    "github.com/asynkron/protoactor-go/cluster/clusterproviders/test"
     "github.com/asynkron/protoactor-go/remote"
     "redditclone/internal/actors"
+    "redditclone/internal/metrics"
+    "redditclone/internal/registry"
+    "redditclone/internal/tracing"
     //"redditclone/internal/messages"
 )
 
 func main() {
+    host := flag.String("host", "127.0.0.1", "address for the engine to listen on")
     port := flag.Int("port", 8090, "port for the engine to listen on")
+    advertise := flag.String("advertise", "",
+        "host other engines and clients reach this one at, if not the -host it listens on (e.g. when listening on 0.0.0.0)")
+    idempotencyWindow := flag.Duration("idempotency-window", actors.DefaultManagerConfig().IdempotencyWindow,
+        "how long managers remember responses to create requests with an idempotency key")
+    shards := flag.Int("shards", actors.DefaultManagerConfig().Shards,
+        "how many shards the post and comment managers are each split into; the same on every engine of a cluster")
+    peers := flag.String("peers", "",
+        "comma-separated cluster ports (host:port) of every engine in the cluster, this one included, as a static seed list")
+    clusterPort := flag.Int("cluster-port", 6330, "port this engine answers cluster membership checks on, with -peers")
+    registryDir := flag.String("registry", "",
+        "directory the engines of a cluster on one machine register in and find each other through, instead of -peers")
+    memberTTL := flag.Duration("member-ttl", 3*time.Second,
+        "how long an engine in -registry may go without a heartbeat before the others take it to have failed")
+    storeDir := flag.String("store", "", "directory to keep users, subreddits, posts and comments in, shared by the engines of a cluster; empty keeps them in memory")
+    passivateAfter := flag.Duration("passivate-after", actors.DefaultManagerConfig().PassivateAfter,
+        "how long a user or subreddit goes without a request before it is deactivated; 0 never")
+    maxRestarts := flag.Int("max-restarts", actors.DefaultManagerConfig().Supervision.MaxRestarts,
+        "how many times a failing manager is restarted within -restart-window before the engine gives up and exits")
+    restartWindow := flag.Duration("restart-window", actors.DefaultManagerConfig().Supervision.RestartWindow,
+        "the window -max-restarts counts a manager's failures over")
+    mailboxSize := flag.Int("mailbox-size", actors.DefaultManagerConfig().Mailboxes[actors.UserManagerName].Size,
+        "how many requests each manager, and each post and comment shard, queues before turning more away; 0 is unbounded")
+    mailboxPolicy := flag.String("mailbox-policy", "reject",
+        "what a full mailbox does: reject the new request, or drop-oldest to drop the one waiting longest; both answer OVERLOADED")
+    mailboxes := flag.String("mailboxes", "",
+        "per-manager overrides of -mailbox-size and -mailbox-policy, as name=size[:policy],... (e.g. post-manager=20000:drop-oldest)")
+    queueReport := flag.Duration("queue-report", 10*time.Second,
+        "how often to log managers' queue depths and turned-away requests, when there are any; 0 never")
+    metricsAddr := flag.String("metrics", "",
+        "address to serve Prometheus metrics on at /metrics (e.g. :9090); empty serves none")
+    spans := flag.String("spans", "",
+        "file to append a trace span to, as a JSON line, for every request the managers, shards and grains answer; engines and simulators may share one")
     flag.Parse()
 
+    if *peers != "" && *registryDir != "" {
+        log.Fatal("Use -peers or -registry, not both")
+    }
+    if (*peers != "" || *registryDir != "") && *storeDir == "" {
+        // Grains move between engines, which only works if they all see
+        // what the others saved
+        log.Fatal("A cluster of engines needs a shared -store")
+    }
+
     // Create the actor system
     system := actor.NewActorSystem()
 
-    // Configure remote
-    remoteConfig := remote.Configure("127.0.0.1", *port)
-    remoting := remote.NewRemote(system, remoteConfig)
-
-    // Create props for each actor type
-    userManagerProps := actor.PropsFromProducer(func() actor.Actor {
-        return actors.NewUserManagerActor()
-    })
-
-    subredditManagerProps := actor.PropsFromProducer(func() actor.Actor {
-        return actors.NewSubRedditManagerActor()
-    })
+    config := actors.DefaultManagerConfig()
+    config.IdempotencyWindow = *idempotencyWindow
+    config.Shards = *shards
+    config.PassivateAfter = *passivateAfter
+    policy, err := actors.ParseMailboxPolicy(*mailboxPolicy)
+    if err != nil {
+        log.Fatal(err)
+    }
+    for name := range config.Mailboxes {
+        config.Mailboxes[name] = actors.MailboxConfig{Size: *mailboxSize, Policy: policy}
+    }
+    if err := actors.ParseMailboxes(*mailboxes, config.Mailboxes); err != nil {
+        log.Fatal(err)
+    }
+    config.Supervision.MaxRestarts = *maxRestarts
+    config.Supervision.RestartWindow = *restartWindow
+    config.Supervision.Escalate = func(manager *actor.PID, reason interface{}) {
+        // Everything else is in the store, so a fresh engine picks up
+        // where this one left off
+        log.Fatalf("Manager %v keeps failing (%v), exiting", manager.Id, reason)
+    }
+    if *storeDir != "" {
+        store, err := actors.NewFileStore(*storeDir)
+        if err != nil {
+            log.Fatalf("Failed to open the store: %v", err)
+        }
+        config.Store = store
+    }
 
-    postManagerProps := actor.PropsFromProducer(func() actor.Actor {
-        return actors.NewPostManagerActor()
-    })
+    // Observe the managers and shards before they are spawned, as they are
+    // given the observer then
+    var engineMetrics *metrics.Metrics
+    if *metricsAddr != "" {
+        engineMetrics = metrics.New()
+        engineMetrics.Watch(system)
+        engineMetrics.CollectMailboxes(config.MailboxStats)
+        if err := engineMetrics.CollectEntities(config.Store); err != nil {
+            log.Fatalf("Failed to count what the store holds: %v", err)
+        }
+        config.Observer = engineMetrics
+    }
 
-    commentManagerProps := actor.PropsFromProducer(func() actor.Actor {
-        return actors.NewCommentManagerActor()
-    })
+    // Trace before the cluster starts, as its kinds are given the tracer
+    var spanFile *tracing.File
+    if *spans != "" {
+        spanFile, err = tracing.OpenFile(*spans, "engine", fmt.Sprintf("%s:%d", *host, *port))
+        if err != nil {
+            log.Fatalf("Failed to open %s: %v", *spans, err)
+        }
+        config.Tracer = spanFile.Tracer("redditclone/internal/actors")
+    }
 
-    messageManagerProps := actor.PropsFromProducer(func() actor.Actor {
-        return actors.NewMessageManagerActor()
-    })
+    // Join the cluster the grains live in, which starts remoting. Alone,
+    // the cluster is this engine; with peers, each engine finds the others
+    // by checking their cluster ports, and with a registry through the
+    // files they keep in it. Either way an engine that stops answering is
+    // dropped and its grains move to the others, which load them from the
+    // store.
+    var provider cluster.ClusterProvider = test.NewTestProvider(test.NewInMemAgent())
+    if *peers != "" {
+        provider = automanaged.NewWithConfig(2*time.Second, *clusterPort, strings.Split(*peers, ",")...)
+    } else if *registryDir != "" {
+        provider = registry.New(*registryDir, *memberTTL)
+    }
+    var remoteOptions []remote.ConfigOption
+    if *advertise != "" {
+        remoteOptions = append(remoteOptions, remote.WithAdvertisedHost(fmt.Sprintf("%s:%d", *advertise, *port)))
+    }
+    remoteConfig := remote.Configure(*host, *port, remoteOptions...)
+    config.Cluster = actors.StartCluster(system, remoteConfig, provider, config)
+    log.Printf("Remote system started on %s:%d", *host, *port)
 
-    // Register actors with the remote system
-    remoting.Register("user-manager", userManagerProps)
-    remoting.Register("subreddit-manager", subredditManagerProps)
-    remoting.Register("post-manager", postManagerProps)
-    remoting.Register("comment-manager", commentManagerProps)
-    remoting.Register("message-manager", messageManagerProps)
-
-    // Start the remote system
-    remoting.Start()
-    log.Printf("Remote system started on port %d", *port)
+    // Spawn the manager actors under the names clients address them by
+    managers, err := actors.SpawnManagers(system.Root, config)
+    if err != nil {
+        log.Fatalf("Failed to spawn managers: %v", err)
+    }
 
+    log.Printf("Reddit engine started on port %d", *port)
+    log.Printf("UserManager started with PID: %v", managers.UserManager)
+    log.Printf("SubReddit Manager PID: %v", managers.SubredditManager)
+    log.Printf("Post Manager PID: %v (%d shards)", managers.PostManager, config.Shards)
+    log.Printf("Comment Manager PID: %v (%d shards)", managers.CommentManager, config.Shards)
+    log.Printf("Message Manager PID: %v", managers.MessageManager)
 
-    // Spawn the manager actors
-    userManager := system.Root.Spawn(userManagerProps)
-    subRedditManager := system.Root.Spawn(subredditManagerProps)
-    postManager := system.Root.Spawn(postManagerProps)
-    commentManager := system.Root.Spawn(commentManagerProps)
-    messageManager := system.Root.Spawn(messageManagerProps)
+    if *queueReport > 0 {
+        go reportQueues(config.MailboxStats, *queueReport)
+    }
+    if engineMetrics != nil {
+        go func() {
+            mux := http.NewServeMux()
+            mux.Handle("/metrics", engineMetrics.Handler())
+            log.Printf("Serving metrics on %s/metrics", *metricsAddr)
+            log.Fatal(http.ListenAndServe(*metricsAddr, mux))
+        }()
+    }
 
-    log.Printf("Reddit engine started on port %d", *port)
-    log.Printf("UserManager started with PID: %v", userManager)
-    log.Printf("SubReddit Manager PID: %v", subRedditManager)
-    log.Printf("Post Manager PID: %v", postManager)
-    log.Printf("Comment Manager PID: %v", commentManager)
-    log.Printf("Message Manager PID: %v", messageManager)
+    // Keep the engine running until it is stopped, then write out the
+    // spans still waiting to be exported
+    stop := make(chan os.Signal, 1)
+    signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+    <-stop
+    if spanFile != nil {
+        if err := spanFile.Close(); err != nil {
+            log.Printf("Failed to write %s: %v", *spans, err)
+        }
+    }
+}
 
-    // Keep the engine running
-    select {}
-}
\ No newline at end of file
+// reportQueues logs, every interval, each manager with requests waiting or
+// turned away since the last report.
+func reportQueues(stats *actors.MailboxStats, interval time.Duration) {
+    last := make(map[string]actors.MailboxSnapshot)
+    for range time.Tick(interval) {
+        for _, mailbox := range stats.Snapshot() {
+            before := last[mailbox.Manager]
+            last[mailbox.Manager] = mailbox
+            rejected, dropped := mailbox.Rejected-before.Rejected, mailbox.Dropped-before.Dropped
+            if mailbox.Queued > 0 || rejected > 0 || dropped > 0 {
+                log.Printf("%s: %d queued, %d rejected and %d dropped in the last %v",
+                    mailbox.Manager, mailbox.Queued, rejected, dropped, interval)
+            }
+        }
+    }
+}
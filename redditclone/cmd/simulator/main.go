@@ -2,63 +2,290 @@
 package main
 
 import (
-    "context"
     "flag"
     "fmt"
+    "log"
+    "os"
+    "os/signal"
+    "strings"
+    "syscall"
     "time"
     "github.com/asynkron/protoactor-go/actor"
     "github.com/asynkron/protoactor-go/remote"
-    "redditclone/internal/actors"
     "redditclone/internal/messages"
+    "redditclone/internal/simulator"
+    "redditclone/internal/tracing"
+    "redditclone/pkg/client"
+    "redditclone/pkg/clock"
 )
 
 func main() {
     enginePort := flag.Int("engine-port", 8090, "port of the reddit engine")
     simulatorPort := flag.Int("port", 8091, "port for the simulator")
     numUsers := flag.Int("users", 10, "number of users to simulate")
-    duration := flag.Duration("duration", 1*time.Minute, "duration to run the simulation")
+    scenarioName := flag.String("scenario", "", "scenario file, or one of the bundled scenarios: "+strings.Join(simulator.BundledScenarios(), ", "))
+    numSubreddits := flag.Int("subreddits", 0, "number of subreddits (default one per 20 users)")
+    zipfExponent := flag.Float64("zipf-s", 1.0, "Zipf exponent for subreddit sizes (0 gives every subreddit every user)")
+    tickInterval := flag.Duration("tick", 100*time.Millisecond, "how often each connected user acts")
+    rate := flag.Float64("rate", 0, "open-loop: schedule this many requests a second regardless of responses (0 for closed-loop)")
+    reportInterval := flag.Duration("report-every", 10*time.Second, "how often to log metrics during the run (negative for never)")
+    reportJSON := flag.String("report-json", "", "write the final metrics report as JSON to this file")
+    reportCSV := flag.String("report-csv", "", "write the final metrics report as CSV to this file")
+    label := flag.String("label", "", "name for this run in the reports, e.g. the engine version")
+    duration := flag.Duration("duration", 1*time.Minute, "duration to run the simulation (default the scenario's length, if it has one)")
+    timeout := flag.Duration("timeout", 5*time.Second, "timeout for each engine request")
+    retries := flag.Int("retries", 3, "retries for idempotent engine requests")
+    seed := flag.Int64("seed", 0, "seed for every random choice (default from the clock)")
+    tracePath := flag.String("trace", "", "record every request to this trace file")
+    replayPath := flag.String("replay", "", "re-issue the requests in this trace file instead of simulating")
+    speed := flag.Float64("speed", 1, "replay pace relative to the recording (0 for as fast as possible)")
+    workers := flag.Int("workers", 0, "coordinate a run split across this many simulator processes started with -join")
+    join := flag.String("join", "", "join the distributed run coordinated at this host:port as a worker")
+    verify := flag.Bool("verify", false, "check the engine against the simulator's model of it once the run ends, exiting 1 on any divergence")
+    spans := flag.String("spans", "",
+        "file to append a trace span to, as a JSON line, for every engine request; point the engines' -spans at it too to see each request end to end")
     flag.Parse()
 
+    if *verify && (*workers > 0 || *join != "") {
+        log.Fatal("-verify only works for runs in a single process")
+    }
+
+    var scenario *simulator.Scenario
+    if *scenarioName != "" {
+        var err error
+        if scenario, err = simulator.LoadScenario(*scenarioName); err != nil {
+            log.Fatal(err)
+        }
+        durationSet := false
+        flag.Visit(func(f *flag.Flag) {
+            durationSet = durationSet || f.Name == "duration"
+        })
+        if !durationSet && scenario.Duration() > 0 {
+            *duration = scenario.Duration()
+        }
+    }
+
     system := actor.NewActorSystem()
     config := remote.Configure("127.0.0.1", *simulatorPort)
     remoting := remote.NewRemote(system, config)
     remoting.Start()
 
+    clientConfig := client.DefaultConfig()
+    clientConfig.Timeout = *timeout
+    clientConfig.Retries = *retries
+    clientConfig.OnHealthChange = func(healthy bool) {
+        if healthy {
+            log.Printf("Engine reachable again")
+        } else {
+            log.Printf("Engine unreachable")
+        }
+    }
+
+    closeSpans := func() {}
+    if *spans != "" {
+        spanFile, err := tracing.OpenFile(*spans, "simulator", config.Address())
+        if err != nil {
+            log.Fatalf("Failed to open %s: %v", *spans, err)
+        }
+        closeSpans = func() {
+            if err := spanFile.Close(); err != nil {
+                log.Printf("Failed to write %s: %v", *spans, err)
+            }
+        }
+        clientConfig.Tracer = spanFile.Tracer("redditclone/pkg/client")
+    }
+    defer closeSpans()
+
     engineAddress := fmt.Sprintf("127.0.0.1:%d", *enginePort)
-    userManagerPID := actor.NewPID(engineAddress, "user-manager")
+    engineClient := client.New(system.Root, engineAddress, clientConfig)
+    defer engineClient.Close()
+
+    if err := engineClient.Ping(); err != nil {
+        log.Fatalf("Engine at %s did not answer: %v", engineAddress, err)
+    }
+
+    if *replayPath != "" {
+        replay(engineClient, *replayPath, simulator.ReplayOptions{Speed: *speed, Label: *label}, *reportJSON, *reportCSV)
+        return
+    }
+
+    if *workers > 0 {
+        if scenario == nil {
+            scenario = simulator.SteadyScenario(*numUsers)
+        }
+        done := make(chan struct{})
+        coordinator, err := system.Root.SpawnNamed(simulator.CoordinatorProps(simulator.CoordinatorConfig{
+            Workers:        *workers,
+            Scenario:       scenario,
+            NumSubreddits:  *numSubreddits,
+            ZipfExponent:   *zipfExponent,
+            TickInterval:   *tickInterval,
+            Rate:           *rate,
+            Seed:           *seed,
+            Duration:       *duration,
+            ReportInterval: *reportInterval,
+            ReportJSON:     *reportJSON,
+            ReportCSV:      *reportCSV,
+            Label:          *label,
+            OnFinish:       func() { close(done) },
+        }, engineClient), simulator.CoordinatorName)
+        if err != nil {
+            log.Fatalf("Failed to start the coordinator: %v", err)
+        }
+        log.Printf("Coordinating at %s; start workers with -join %s", coordinator.Address, coordinator.Address)
+
+        // The coordinator ends the run itself once its workers have begun
+        // and the duration is up
+        run(system, coordinator, done, clock.Real{}, 0, *timeout+20*time.Second)
+        return
+    }
+
+    if *join != "" {
+        coordinator := actor.NewPID(*join, simulator.CoordinatorName)
+        result, err := system.Root.RequestFuture(coordinator, &messages.RegisterWorker{}, *timeout).Result()
+        if err != nil {
+            log.Fatalf("Coordinator at %s did not answer: %v", *join, err)
+        }
+        assignment, ok := result.(*messages.WorkerAssignment)
+        if !ok {
+            log.Fatalf("Coordinator at %s answered with %T", *join, result)
+        }
+        config, err := simulator.WorkerConfig(assignment, coordinator)
+        if err != nil {
+            log.Fatal(err)
+        }
+        log.Printf("Joined as worker %d of %d", assignment.Worker+1, assignment.Workers)
+
+        done := make(chan struct{})
+        config.ReportInterval = *reportInterval
+        config.ReportJSON = *reportJSON
+        config.ReportCSV = *reportCSV
+        config.Label = *label
+        config.TracePath = *tracePath
+        config.OnFinish = func() { close(done) }
+
+        coordinatorGone := watch(system, coordinator)
+        sim := system.Root.Spawn(simulator.Props(config, engineClient))
+        system.Root.Send(sim, &messages.StartSimulation{})
+        run(system, sim, done, clock.Real{}, 0, *timeout+10*time.Second)
+
+        // Exiting straight away can lose the final stats on their way to
+        // the coordinator; it stops once it has everyone's
+        select {
+        case <-coordinatorGone:
+        case <-time.After(5 * time.Second):
+        }
+        return
+    }
+
+    // The run's length is measured on the clock the simulator runs on
+    var runClock clock.Clock = clock.Real{}
+    diverged := false
+    props := simulator.Props(simulator.Config{
+        NumUsers:       *numUsers,
+        Scenario:       scenario,
+        NumSubreddits:  *numSubreddits,
+        ZipfExponent:   *zipfExponent,
+        TickInterval:   *tickInterval,
+        Rate:           *rate,
+        ReportInterval: *reportInterval,
+        ReportJSON:     *reportJSON,
+        ReportCSV:      *reportCSV,
+        Label:          *label,
+        Seed:           *seed,
+        TracePath:      *tracePath,
+        Verify:         *verify,
+        OnVerified:     func(divergences []string) { diverged = len(divergences) > 0 },
+        Clock:          runClock,
+    }, engineClient)
 
-    // Create context with timeout
-    context.Background()
-    
-    // Instead of using RequestFuture directly, create a proper sender actor
-    senderProps := actor.PropsFromFunc(func(context actor.Context) {
+    // Verifying reads back everything the run touched before the simulator
+    // answers the stop
+    stopTimeout := *timeout + 10*time.Second
+    if *verify {
+        stopTimeout += 2 * time.Minute
+    }
+    sim := system.Root.Spawn(props)
+    system.Root.Send(sim, &messages.StartSimulation{NumUsers: int32(*numUsers)})
+    run(system, sim, nil, runClock, *duration, stopTimeout)
+    if diverged {
+        engineClient.Close()
+        closeSpans()
+        os.Exit(1)
+    }
+}
+
+// run waits for the simulation to end, passing on pause and resume. It ends
+// when done is closed, after duration on c if that is positive, or on
+// interrupt; in the last two cases it stops the simulation and logs its
+// final stats.
+func run(system *actor.ActorSystem, sim *actor.PID, done <-chan struct{}, c clock.Clock, duration, stopTimeout time.Duration) {
+    // SIGUSR1 pauses and SIGUSR2 resumes
+    signals := make(chan os.Signal, 1)
+    signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGUSR1, syscall.SIGUSR2)
+    var deadline <-chan time.Time
+    if duration > 0 {
+        deadline = c.After(duration)
+    }
+
+wait:
+    for {
+        select {
+        case <-done:
+            return
+        case <-deadline:
+            break wait
+        case sig := <-signals:
+            switch sig {
+            case syscall.SIGUSR1:
+                system.Root.Send(sim, &messages.PauseSimulation{})
+            case syscall.SIGUSR2:
+                system.Root.Send(sim, &messages.ResumeSimulation{})
+            default:
+                break wait
+            }
+        }
+    }
+
+    // The simulator answers once its in-flight requests are done
+    result, err := system.Root.RequestFuture(sim, &messages.StopSimulation{}, stopTimeout).Result()
+    if err != nil {
+        log.Fatalf("Simulator did not stop cleanly: %v", err)
+    }
+    if stats, ok := result.(*messages.SimulationStats); ok {
+        log.Printf("Final stats: %d users, %d subreddits, %d posts, %d comments, %d messages",
+            stats.RegisteredUsers, stats.ActiveSubreddits, stats.TotalPosts, stats.TotalComments, stats.TotalMessages)
+    }
+}
+
+// watch returns a channel that is closed once pid stops or is lost.
+func watch(system *actor.ActorSystem, pid *actor.PID) <-chan struct{} {
+    gone := make(chan struct{})
+    system.Root.Spawn(actor.PropsFromFunc(func(context actor.Context) {
         switch context.Message().(type) {
-        case *messages.OperationResponse:
-            // Handle response if needed
+        case *actor.Started:
+            context.Watch(pid)
+        case *actor.Terminated:
+            close(gone)
+            context.Stop(context.Self())
+        }
+    }))
+    return gone
+}
+
+func replay(engineClient *client.Client, path string, options simulator.ReplayOptions, reportJSON, reportCSV string) {
+    report, err := simulator.Replay(engineClient, path, options)
+    if err != nil {
+        log.Fatalf("Replay of %s failed: %v", path, err)
+    }
+    if reportJSON != "" {
+        if err := report.WriteJSON(reportJSON); err != nil {
+            log.Printf("Failed to write %s: %v", reportJSON, err)
+        }
+    }
+    if reportCSV != "" {
+        if err := report.WriteCSV(reportCSV); err != nil {
+            log.Printf("Failed to write %s: %v", reportCSV, err)
         }
-    })
-    sender := system.Root.Spawn(senderProps)
-
-    // Send a message using the sender actor
-    system.Root.Send(userManagerPID, &messages.RegisterUserMsg{
-        Username: "test_user",
-    })
-
-    managerPIDs := map[string]*actor.PID{
-		"user-manager":      userManagerPID,
-		"subreddit-manager": actor.NewPID(engineAddress, "subreddit-manager"),
-		"post-manager":      actor.NewPID(engineAddress, "post-manager"),
-		"comment-manager":   actor.NewPID(engineAddress, "comment-manager"),
-		"message-manager":   actor.NewPID(engineAddress, "message-manager"),
-	}
-
-    props := actor.PropsFromProducer(func() actor.Actor {
-        return actors.NewSimulatorActor(*numUsers, managerPIDs)
-    })
-
-    simulator := system.Root.Spawn(props)
-    system.Root.Send(simulator, &messages.StartSimulation{NumUsers: int32(*numUsers)})
-    time.Sleep(*duration)
-
-    system.Root.Stop(sender)
-}
\ No newline at end of file
+    }
+}
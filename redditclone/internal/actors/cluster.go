@@ -0,0 +1,82 @@
+// internal/actors/cluster.go
+package actors
+
+import (
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/cluster"
+    "github.com/asynkron/protoactor-go/cluster/clusterproviders/test"
+    "github.com/asynkron/protoactor-go/cluster/identitylookup/disthash"
+    "github.com/asynkron/protoactor-go/remote"
+    "redditclone/internal/tracing"
+    "redditclone/pkg/clock"
+)
+
+// ClusterName is the cluster every engine joins.
+const ClusterName = "redditclone"
+
+// StartCluster makes system a member of the cluster provider finds,
+// listening on remoteConfig and hosting grains with the store, passivation,
+// shards, mailboxes, observer, tracer and clock from config. Members share out the grains between them
+// and take over those of a member that leaves or fails, so every member
+// must be started with the same store and shard count. It starts remoting,
+// so it comes before SpawnManagers, which needs the cluster it returns.
+func StartCluster(system *actor.ActorSystem, remoteConfig *remote.Config, provider cluster.ClusterProvider, config ManagerConfig) *cluster.Cluster {
+    if config.Store == nil {
+        config.Store = NewMemoryStore()
+    }
+    if config.Clock == nil {
+        config.Clock = clock.Real{}
+    }
+    if config.MailboxStats == nil {
+        config.MailboxStats = NewMailboxStats()
+    }
+    posts := postRing(config.Shards)
+    c := cluster.New(system, cluster.Configure(ClusterName, provider, disthash.New(), remoteConfig,
+        cluster.WithKinds(
+            newUserKind(config.Store, config.PassivateAfter, config.Clock, config.grainOptions(system, UserKind)),
+            newSubredditKind(config.Store, config.PassivateAfter, config.Clock, config.grainOptions(system, SubredditKind)),
+            newPostShardKind(config.Store, config.IdempotencyWindow, config.Clock, posts, config.managerOptions(system, PostManagerName, PostShardKind)),
+            newCommentShardKind(config.Store, config.IdempotencyWindow, config.Clock, commentRing(config.Shards), posts,
+                config.managerOptions(system, CommentManagerName, CommentShardKind)),
+        )))
+    c.StartMember()
+    return c
+}
+
+// StartLocalCluster is StartCluster for a cluster of one, on a free port of
+// the loopback interface, for engines that run in-process.
+func StartLocalCluster(system *actor.ActorSystem, config ManagerConfig) *cluster.Cluster {
+    provider := test.NewTestProvider(test.NewInMemAgent())
+    return StartCluster(system, remote.Configure("127.0.0.1", 0), provider, config)
+}
+
+// requestGrain asks the grain of kind with identity, without holding up the
+// calling actor while the cluster finds or activates it. A grain that
+// can't be reached is answered for with an internal error, and there is no
+// grain for an empty identity, which is answered for as invalid. The
+// request carries the trace context of the one being handled, if any.
+func requestGrain(context actor.Context, c *cluster.Cluster, kind, identity string, msg interface{}) *actor.Future {
+    future := actor.NewFuture(context.ActorSystem(), 10*time.Second)
+    if identity == "" {
+        context.Send(future.PID(), invalidArgument("id", kind+" ID must not be empty"))
+        return future
+    }
+    sender := tracing.Sender(context.ActorSystem(), tracing.Extract(context.MessageHeader()))
+    go func() {
+        res, err := c.Request(identity, kind, msg, cluster.WithContext(sender))
+        if err != nil {
+            res = internalError("%s %s did not answer: %v", kind, identity, err)
+        }
+        context.ActorSystem().Root.Send(future.PID(), res)
+    }()
+    return future
+}
+
+// forwardToGrain answers with whatever the grain answers.
+func forwardToGrain(context actor.Context, c *cluster.Cluster, kind, identity string, msg interface{}) {
+    future := requestGrain(context, c, kind, identity, msg)
+    context.ReenterAfter(future, func(res interface{}, err error) {
+        context.Respond(asResponse(res, err))
+    })
+}
@@ -2,60 +2,228 @@
 package actors
 
 import (
-    "fmt"
-    //"time"
+    "sort"
+    "time"
     "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/cluster"
     "redditclone/internal/messages"
+    "redditclone/pkg/clock"
+    "google.golang.org/protobuf/proto"
     "google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// commentStoreKind is what comment shards save each comment under, by its
+// ID.
+const commentStoreKind = "comment"
+
+func newCommentShardKind(store GrainStore, idempotencyWindow time.Duration, clock clock.Clock, ring, postRing *shardRing, options []actor.PropsOption) *cluster.Kind {
+    return cluster.NewKind(CommentShardKind, actor.PropsFromProducer(func() actor.Actor {
+        return NewCommentManagerActor(store, idempotencyWindow, clock, ring, postRing)
+    }, append(options, recoverGrain(CommentShardKind))...))
+}
+
+// Receive handles the requests the comment manager's router sends this
+// shard. Like a post shard, it loads the comments it owns when activated
+// and saves a comment before answering any request that changed it.
 func (state *CommentManagerActor) Receive(context actor.Context) {
     switch msg := context.Message().(type) {
-    case *actor.Started:
-        state.UserManager = context.Parent()
+    case *cluster.ClusterInit:
+        state.Identity = msg.Identity.Identity
+        state.Cluster = msg.Cluster
+        state.LoadErr = state.load()
 
     case *messages.CreateCommentMsg:
-        commentID := fmt.Sprintf("comment_%d", len(state.Comments)+1)
-        newComment := &messages.Comment{
-            Id:        commentID,
-            Content:   msg.Content,
-            AuthorId:  msg.AuthorId,
-            ParentId:  msg.ParentId,
-            Upvotes:   0,
-            Downvotes: 0,
-            Timestamp: timestamppb.Now(),
-        }
-        
-        state.Comments[commentID] = newComment
-
-        context.Respond(&messages.OperationResponse{
-            Success: true,
-            Id:      commentID,
-            Result: &messages.OperationResponse_Comment{
-                Comment: newComment,
-            },
+        if msg.Content == "" {
+            context.Respond(invalidArgument("content", "must not be empty"))
+            return
+        }
+        if !state.loaded(context) {
+            return
+        }
+
+        if !state.Idempotency.begin(context, msg.IdempotencyKey) {
+            return
+        }
+
+        if msg.ParentId != "" {
+            if parent, exists := state.Comments[msg.ParentId]; !exists || parent.PostId != msg.PostId {
+                state.Idempotency.finish(context, msg.IdempotencyKey,
+                    notFound("parent_id", "comment", msg.ParentId))
+                return
+            }
+        }
+
+        // Posts belong to the post manager, so check the post exists there
+        future := requestGrain(context, state.Cluster, PostShardKind, state.PostRing.shard(msg.PostId), &messages.GetPostMsg{
+            PostId: msg.PostId,
+        })
+
+        context.ReenterAfter(future, func(res interface{}, err error) {
+            if response := asResponse(res, err); !response.Success {
+                state.Idempotency.finish(context, msg.IdempotencyKey, response)
+                return
+            }
+
+            commentID := state.Ring.nextID(state.Identity, "comment", &state.LastID)
+            newComment := &messages.Comment{
+                Id:        commentID,
+                Content:   msg.Content,
+                AuthorId:  msg.AuthorId,
+                ParentId:  msg.ParentId,
+                PostId:    msg.PostId,
+                Upvotes:   0,
+                Downvotes: 0,
+                Timestamp: timestamppb.New(state.Clock.Now()),
+            }
+
+            state.Comments[commentID] = newComment
+            state.ByPost[msg.PostId] = append(state.ByPost[msg.PostId], commentID)
+            state.Votes[commentID] = make(map[string]bool)
+            if !state.save(context, commentID) {
+                return
+            }
+
+            state.Idempotency.finish(context, msg.IdempotencyKey, &messages.OperationResponse{
+                Success: true,
+                Id:      commentID,
+                Result: &messages.OperationResponse_Comment{
+                    Comment: proto.Clone(newComment).(*messages.Comment),
+                },
+            })
         })
 
     case *messages.VoteMsg:
+        if msg.UserId == "" {
+            context.Respond(invalidArgument("user_id", "must not be empty"))
+            return
+        }
+        if !state.loaded(context) {
+            return
+        }
+
         if comment, exists := state.Comments[msg.ItemId]; exists {
-            if msg.IsUpvote {
-                comment.Upvotes++
-            } else {
-                comment.Downvotes++
+            upDelta, downDelta := applyVote(state.Votes[comment.Id], msg.UserId, msg.IsUpvote)
+            comment.Upvotes += upDelta
+            comment.Downvotes += downDelta
+            if upDelta != 0 || downDelta != 0 {
+                if !state.save(context, comment.Id) {
+                    return
+                }
             }
-            
-            // Update author's karma
-            context.Request(state.UserManager, &messages.VoteMsg{
-                UserId: comment.AuthorId,
-                IsUpvote: msg.IsUpvote,
-            })
 
-            context.Respond(&messages.OperationResponse{Success: true})
+            answerVote(context, state.Cluster, comment.AuthorId, msg, state.Clock, &messages.OperationResponse{
+                Success: true,
+                Id:      comment.Id,
+                Result: &messages.OperationResponse_Comment{
+                    Comment: proto.Clone(comment).(*messages.Comment),
+                },
+            })
         } else {
+            context.Respond(notFound("item_id", "comment", msg.ItemId))
+        }
+
+    case *messages.GetCommentsMsg:
+        if !state.loaded(context) {
+            return
+        }
+        future := requestGrain(context, state.Cluster, PostShardKind, state.PostRing.shard(msg.PostId), &messages.GetPostMsg{
+            PostId: msg.PostId,
+        })
+
+        context.ReenterAfter(future, func(res interface{}, err error) {
+            if response := asResponse(res, err); !response.Success {
+                context.Respond(response)
+                return
+            }
+
             context.Respond(&messages.OperationResponse{
-                Success: false,
-                Error: "comment not found",
+                Success:  true,
+                Comments: state.commentTree(msg.PostId),
             })
+        })
+    }
+}
+
+// commentTree returns copies of a post's comments with replies nested under
+// their parents, oldest first at every level.
+func (state *CommentManagerActor) commentTree(postID string) []*messages.Comment {
+    roots := make([]*messages.Comment, 0)
+    copies := make(map[string]*messages.Comment)
+
+    for _, commentID := range state.ByPost[postID] {
+        comment := proto.Clone(state.Comments[commentID]).(*messages.Comment)
+        copies[commentID] = comment
+
+        if parent, exists := copies[comment.ParentId]; exists {
+            parent.Children = append(parent.Children, comment)
+        } else {
+            roots = append(roots, comment)
         }
     }
-}
\ No newline at end of file
+    return roots
+}
+
+// load claims the shard, reads back the comments it owns, rebuilding each
+// post's list in the order they were made, and carries on numbering after
+// the highest of them.
+func (state *CommentManagerActor) load() error {
+    lease, err := state.Store.Claim(CommentShardKind, state.Identity)
+    if err != nil {
+        return err
+    }
+    state.Lease = lease
+
+    commentIDs, err := state.Store.Keys(commentStoreKind)
+    if err != nil {
+        return err
+    }
+    sort.Slice(commentIDs, func(i, j int) bool {
+        return idNumber(commentIDs[i]) < idNumber(commentIDs[j])
+    })
+    for _, commentID := range commentIDs {
+        if !state.Ring.owns(state.Identity, commentID) {
+            continue
+        }
+        stored := &messages.StoredComment{}
+        if _, err := state.Store.Load(commentStoreKind, commentID, stored); err != nil {
+            return err
+        }
+        comment := stored.Comment
+        state.Comments[commentID] = comment
+        state.ByPost[comment.PostId] = append(state.ByPost[comment.PostId], commentID)
+        state.Votes[commentID] = stored.Votes
+        if state.Votes[commentID] == nil {
+            state.Votes[commentID] = make(map[string]bool)
+        }
+        if n := idNumber(commentID); n > state.LastID {
+            state.LastID = n
+        }
+    }
+    return nil
+}
+
+// loaded answers with an error if the shard's comments couldn't be loaded,
+// and stops it so the next request activates it afresh.
+func (state *CommentManagerActor) loaded(context actor.Context) bool {
+    if state.LoadErr == nil {
+        return true
+    }
+    context.Respond(internalError("%s could not be loaded: %v", state.Identity, state.LoadErr))
+    context.Stop(context.Self())
+    return false
+}
+
+// save writes a comment through to the store, stopping the shard if that
+// fails, as a post shard does.
+func (state *CommentManagerActor) save(context actor.Context, commentID string) bool {
+    err := state.Store.Save(state.Lease, commentStoreKind, commentID, &messages.StoredComment{
+        Comment: state.Comments[commentID],
+        Votes:   state.Votes[commentID],
+    })
+    if err == nil {
+        return true
+    }
+    context.Respond(internalError("comment %s could not be saved: %v", commentID, err))
+    context.Stop(context.Self())
+    return false
+}
@@ -0,0 +1,76 @@
+// internal/actors/errors.go
+package actors
+
+import (
+    "fmt"
+    "redditclone/internal/messages"
+)
+
+// Failed OperationResponses. Error keeps a readable message for logs;
+// clients should look at Code and Details.
+
+func notFound(field, entityType, entityID string) *messages.OperationResponse {
+    return &messages.OperationResponse{
+        Success: false,
+        Error: fmt.Sprintf("%s %q not found", entityType, entityID),
+        Code: messages.ErrorCode_NOT_FOUND,
+        Details: &messages.ErrorDetails{
+            Field:      field,
+            EntityType: entityType,
+            EntityId:   entityID,
+        },
+    }
+}
+
+func alreadyExists(field, entityType, value string) *messages.OperationResponse {
+    return &messages.OperationResponse{
+        Success: false,
+        Error: fmt.Sprintf("%s with %s %q already exists", entityType, field, value),
+        Code: messages.ErrorCode_ALREADY_EXISTS,
+        Details: &messages.ErrorDetails{
+            Field:      field,
+            EntityType: entityType,
+            EntityId:   value,
+        },
+    }
+}
+
+func permissionDenied(userID, action, entityType, entityID string) *messages.OperationResponse {
+    return &messages.OperationResponse{
+        Success: false,
+        Error: fmt.Sprintf("%s may not %s %s %q", userID, action, entityType, entityID),
+        Code: messages.ErrorCode_PERMISSION_DENIED,
+        Details: &messages.ErrorDetails{
+            Field:      "user_id",
+            EntityType: entityType,
+            EntityId:   entityID,
+        },
+    }
+}
+
+func invalidArgument(field, reason string) *messages.OperationResponse {
+    return &messages.OperationResponse{
+        Success: false,
+        Error: fmt.Sprintf("invalid %s: %s", field, reason),
+        Code: messages.ErrorCode_INVALID_ARGUMENT,
+        Details: &messages.ErrorDetails{
+            Field: field,
+        },
+    }
+}
+
+func internalError(format string, args ...interface{}) *messages.OperationResponse {
+    return &messages.OperationResponse{
+        Success: false,
+        Error: fmt.Sprintf(format, args...),
+        Code: messages.ErrorCode_INTERNAL,
+    }
+}
+
+func overloaded(manager string) *messages.OperationResponse {
+    return &messages.OperationResponse{
+        Success: false,
+        Error: fmt.Sprintf("%s is overloaded, try again later", manager),
+        Code: messages.ErrorCode_OVERLOADED,
+    }
+}
@@ -0,0 +1,111 @@
+// internal/actors/idempotency.go
+package actors
+
+import (
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "redditclone/internal/messages"
+    "redditclone/pkg/clock"
+    "google.golang.org/protobuf/proto"
+)
+
+// idempotencyCache remembers the responses a manager sent for idempotency
+// keys so that repeated create requests get the original answer.
+//
+// A key is claimed when its request starts. Repeats that arrive while the
+// request is still in flight (e.g. waiting on another manager) are answered
+// when it finishes. Failed responses are not remembered, so a retry after a
+// failure is processed again.
+//
+// The cache lives in the memory of one manager or shard and is not saved
+// with the grains. A retry that reaches a restarted manager, or another
+// engine after a failover, is processed again: a repeated register or
+// subreddit create then fails with ALREADY_EXISTS, and a repeated post,
+// comment or direct message is created a second time.
+type idempotencyCache struct {
+    window  time.Duration
+    clock   clock.Clock
+    entries map[string]*idempotencyEntry
+    order   []string // keys in the order they were claimed
+}
+
+type idempotencyEntry struct {
+    response *messages.OperationResponse // nil while in flight
+    expires  time.Time
+    waiting  []*actor.PID
+}
+
+// newIdempotencyCache returns a cache that keeps responses for window, as
+// clock tells it. A zero window turns idempotency keys off.
+func newIdempotencyCache(window time.Duration, clock clock.Clock) *idempotencyCache {
+    return &idempotencyCache{
+        window:  window,
+        clock:   clock,
+        entries: make(map[string]*idempotencyEntry),
+    }
+}
+
+// begin claims key for the current request. It returns false if key was
+// seen before, in which case the request has been (or will be) answered.
+func (c *idempotencyCache) begin(context actor.Context, key string) bool {
+    if key == "" || c.window <= 0 {
+        return true
+    }
+    c.expire(c.clock.Now())
+
+    if entry, exists := c.entries[key]; exists {
+        if entry.response != nil {
+            reply(context, entry.response)
+        } else if context.Sender() != nil {
+            entry.waiting = append(entry.waiting, context.Sender())
+        }
+        return false
+    }
+
+    c.entries[key] = &idempotencyEntry{}
+    c.order = append(c.order, key)
+    return true
+}
+
+// finish answers the request that claimed key and any repeats waiting on it.
+func (c *idempotencyCache) finish(context actor.Context, key string, response *messages.OperationResponse) {
+    reply(context, response)
+
+    entry, exists := c.entries[key]
+    if !exists {
+        return
+    }
+    for _, waiter := range entry.waiting {
+        context.Send(waiter, response)
+    }
+
+    if response.Success {
+        entry.response = proto.Clone(response).(*messages.OperationResponse)
+        entry.expires = c.clock.Now().Add(c.window)
+        entry.waiting = nil
+    } else {
+        delete(c.entries, key)
+    }
+}
+
+// expire drops remembered responses older than the window. It stops at the
+// first key still in flight; those are rare and short lived.
+func (c *idempotencyCache) expire(now time.Time) {
+    for len(c.order) > 0 {
+        entry, exists := c.entries[c.order[0]]
+        if exists {
+            if entry.response == nil || now.Before(entry.expires) {
+                return
+            }
+            delete(c.entries, c.order[0])
+        }
+        c.order = c.order[1:]
+    }
+}
+
+// reply responds to the sender, if the request has one.
+func reply(context actor.Context, response *messages.OperationResponse) {
+    if context.Sender() != nil {
+        context.Respond(response)
+    }
+}
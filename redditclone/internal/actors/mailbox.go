@@ -0,0 +1,258 @@
+// internal/actors/mailbox.go
+package actors
+
+import (
+    "container/list"
+    "fmt"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+)
+
+// MailboxPolicy is what a full mailbox does with one more message.
+type MailboxPolicy int
+
+const (
+    // RejectWhenFull answers the new request OVERLOADED without queueing
+    // it, so callers back off instead of piling on.
+    RejectWhenFull MailboxPolicy = iota
+
+    // DropOldest makes room by dropping the request that has waited
+    // longest, answering it OVERLOADED. It suits low-priority work whose
+    // newest requests matter most. A dropped request is only let go of once
+    // the actor gets to it, so while Size dropped ones are still queued,
+    // new requests are turned away as under RejectWhenFull.
+    DropOldest
+)
+
+func (p MailboxPolicy) String() string {
+    if p == DropOldest {
+        return "drop-oldest"
+    }
+    return "reject"
+}
+
+func ParseMailboxPolicy(s string) (MailboxPolicy, error) {
+    switch s {
+    case "reject":
+        return RejectWhenFull, nil
+    case "drop-oldest":
+        return DropOldest, nil
+    }
+    return 0, fmt.Errorf("unknown mailbox policy %q, want reject or drop-oldest", s)
+}
+
+// MailboxConfig bounds a manager's mailbox. A Size of zero leaves it
+// unbounded. The post and comment managers' shards each get a mailbox of
+// their manager's size, as well as the router in front of them.
+type MailboxConfig struct {
+    Size   int
+    Policy MailboxPolicy
+}
+
+// ParseMailboxes reads per-manager mailbox settings written as
+// name=size[:policy], comma separated, e.g.
+// "post-manager=5000,user-manager=1000:drop-oldest", into mailboxes.
+func ParseMailboxes(spec string, mailboxes map[string]MailboxConfig) error {
+    for _, entry := range strings.Split(spec, ",") {
+        if entry == "" {
+            continue
+        }
+        name, setting, found := strings.Cut(entry, "=")
+        if !found {
+            return fmt.Errorf("mailbox %q: want name=size[:policy]", entry)
+        }
+        size, policy, _ := strings.Cut(setting, ":")
+        config := MailboxConfig{}
+        if _, err := fmt.Sscanf(size, "%d", &config.Size); err != nil || config.Size < 0 {
+            return fmt.Errorf("mailbox %q: bad size %q", entry, size)
+        }
+        if policy != "" {
+            var err error
+            if config.Policy, err = ParseMailboxPolicy(policy); err != nil {
+                return fmt.Errorf("mailbox %q: %w", entry, err)
+            }
+        }
+        mailboxes[strings.TrimSpace(name)] = config
+    }
+    return nil
+}
+
+// MailboxStats counts, for each manager and each kind of grain, the
+// messages waiting in its mailboxes and those turned away because they
+// were full. It is safe to read while the managers run.
+type MailboxStats struct {
+    mu       sync.Mutex
+    managers map[string]*mailboxCounters
+}
+
+type mailboxCounters struct {
+    queued   atomic.Int64
+    rejected atomic.Int64
+    dropped  atomic.Int64
+}
+
+// MailboxSnapshot is one manager's counters at a moment. Queued adds up
+// every mailbox of the manager, shards included.
+type MailboxSnapshot struct {
+    Manager  string
+    Queued   int64
+    Rejected int64
+    Dropped  int64
+}
+
+func NewMailboxStats() *MailboxStats {
+    return &MailboxStats{managers: make(map[string]*mailboxCounters)}
+}
+
+func (s *MailboxStats) counters(manager string) *mailboxCounters {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    counters, exists := s.managers[manager]
+    if !exists {
+        counters = &mailboxCounters{}
+        s.managers[manager] = counters
+    }
+    return counters
+}
+
+// Snapshot returns every manager's counters, by name.
+func (s *MailboxStats) Snapshot() []MailboxSnapshot {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    snapshot := make([]MailboxSnapshot, 0, len(s.managers))
+    for manager, counters := range s.managers {
+        snapshot = append(snapshot, MailboxSnapshot{
+            Manager:  manager,
+            Queued:   counters.queued.Load(),
+            Rejected: counters.rejected.Load(),
+            Dropped:  counters.dropped.Load(),
+        })
+    }
+    sort.Slice(snapshot, func(i, j int) bool {
+        return snapshot[i].Manager < snapshot[j].Manager
+    })
+    return snapshot
+}
+
+// boundedMailbox is protoactor's unbounded mailbox with a limit on the
+// requests it queues. System messages are never turned away, and neither
+// are messages nobody is waiting on, such as a receive timeout.
+//
+// Protoactor's own bounded mailboxes block the sender when full or drop
+// the oldest message without a word, so this one keeps the limit itself:
+// it counts the requests waiting, and drops the oldest by answering it
+// OVERLOADED and skipping it when the mailbox gets to it. Protoactor's
+// queue can't give up a message early, so dropped requests count against
+// a limit of their own, and a mailbox holds at most twice its Size.
+type boundedMailbox struct {
+    actor.Mailbox
+    system   *actor.ActorSystem
+    manager  string
+    config   MailboxConfig
+    counters *mailboxCounters
+    traced   bool
+
+    mu       sync.Mutex
+    requests *list.List // requests waiting, oldest first
+    waiting  map[*actor.MessageEnvelope]*list.Element
+    dropped  map[*actor.MessageEnvelope]bool // still queued, to be skipped
+}
+
+// newMailbox makes mailboxes for the manager with the given name, counting
+// into stats. Traced ones stamp each request with when it was queued. The
+// middlewares see what protoactor's mailbox does, as they would without
+// the limit.
+func newMailbox(system *actor.ActorSystem, manager string, config MailboxConfig, stats *MailboxStats, traced bool, middlewares ...actor.MailboxMiddleware) actor.MailboxProducer {
+    counters := stats.counters(manager)
+    unbounded := actor.Unbounded(middlewares...)
+    return func() actor.Mailbox {
+        return &boundedMailbox{
+            Mailbox:  unbounded(),
+            system:   system,
+            manager:  manager,
+            config:   config,
+            counters: counters,
+            traced:   traced,
+            requests: list.New(),
+            waiting:  make(map[*actor.MessageEnvelope]*list.Element),
+            dropped:  make(map[*actor.MessageEnvelope]bool),
+        }
+    }
+}
+
+func (m *boundedMailbox) PostUserMessage(message interface{}) {
+    envelope, _ := message.(*actor.MessageEnvelope)
+    if envelope != nil && envelope.Sender == nil {
+        envelope = nil
+    }
+    if envelope != nil && m.traced {
+        envelope.SetHeader(queuedAtHeader, strconv.FormatInt(time.Now().UnixNano(), 10))
+    }
+
+    var turnedAway *actor.MessageEnvelope
+    m.mu.Lock()
+    if envelope != nil && m.config.Size > 0 && m.requests.Len() >= m.config.Size {
+        if m.config.Policy == DropOldest && len(m.dropped) < m.config.Size {
+            turnedAway = m.requests.Remove(m.requests.Front()).(*actor.MessageEnvelope)
+            delete(m.waiting, turnedAway)
+            m.dropped[turnedAway] = true
+            m.counters.dropped.Add(1)
+            m.counters.queued.Add(-1)
+        } else {
+            m.mu.Unlock()
+            m.counters.rejected.Add(1)
+            m.system.Root.Send(envelope.Sender, overloaded(m.manager))
+            return
+        }
+    }
+    if envelope != nil {
+        m.waiting[envelope] = m.requests.PushBack(envelope)
+    }
+    m.counters.queued.Add(1)
+    m.mu.Unlock()
+
+    m.Mailbox.PostUserMessage(message)
+    if turnedAway != nil {
+        m.system.Root.Send(turnedAway.Sender, overloaded(m.manager))
+    }
+}
+
+func (m *boundedMailbox) RegisterHandlers(invoker actor.MessageInvoker, dispatcher actor.Dispatcher) {
+    m.Mailbox.RegisterHandlers(&boundedInvoker{MessageInvoker: invoker, mailbox: m}, dispatcher)
+}
+
+// taken notes that message has left the mailbox and reports whether it is
+// still to be handled, or was dropped.
+func (m *boundedMailbox) taken(message interface{}) bool {
+    envelope, _ := message.(*actor.MessageEnvelope)
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    if m.dropped[envelope] {
+        delete(m.dropped, envelope)
+        return false
+    }
+    if element, exists := m.waiting[envelope]; exists {
+        m.requests.Remove(element)
+        delete(m.waiting, envelope)
+    }
+    m.counters.queued.Add(-1)
+    return true
+}
+
+// boundedInvoker hands the actor the messages its mailbox takes, except
+// those it dropped.
+type boundedInvoker struct {
+    actor.MessageInvoker
+    mailbox *boundedMailbox
+}
+
+func (i *boundedInvoker) InvokeUserMessage(message interface{}) {
+    if i.mailbox.taken(message) {
+        i.MessageInvoker.InvokeUserMessage(message)
+    }
+}
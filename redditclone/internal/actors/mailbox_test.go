@@ -0,0 +1,67 @@
+// internal/actors/mailbox_test.go
+package actors
+
+import (
+    "testing"
+    "time"
+
+    "github.com/asynkron/protoactor-go/actor"
+    "redditclone/internal/messages"
+)
+
+func TestDropOldestMailboxStaysBounded(t *testing.T) {
+    const size, flood = 10, 1000
+    system := actor.NewActorSystem()
+    defer system.Shutdown()
+
+    // The actor holds on to its first message until released, so
+    // everything after it waits in the mailbox
+    var mailbox *boundedMailbox
+    produce := newMailbox(system, "flooded", MailboxConfig{Size: size, Policy: DropOldest}, NewMailboxStats(), false)
+    blocked, release := make(chan struct{}), make(chan struct{})
+    handled := 0
+    pid := system.Root.Spawn(actor.PropsFromFunc(func(context actor.Context) {
+        if _, ok := context.Message().(string); !ok {
+            return
+        }
+        if handled++; handled == 1 {
+            close(blocked)
+            <-release
+        }
+        context.Respond(&messages.OperationResponse{Success: true})
+    }, actor.WithMailbox(func() actor.Mailbox {
+        mailbox = produce().(*boundedMailbox)
+        return mailbox
+    })))
+
+    first := system.Root.RequestFuture(pid, "first", 10*time.Second)
+    <-blocked
+    futures := make([]*actor.Future, flood)
+    for i := range futures {
+        futures[i] = system.Root.RequestFuture(pid, "flood", 10*time.Second)
+    }
+    if queued := mailbox.UserMessageCount(); queued > 2*size {
+        t.Errorf("%d messages queued after a flood of %d, want at most %d", queued, flood, 2*size)
+    }
+
+    close(release)
+    if _, err := first.Result(); err != nil {
+        t.Fatalf("first request: %v", err)
+    }
+    answered, overloaded := 0, 0
+    for _, future := range futures {
+        result, err := future.Result()
+        if err != nil {
+            t.Fatalf("flooding request: %v", err)
+        }
+        if response := result.(*messages.OperationResponse); response.Success {
+            answered++
+        } else if response.Code == messages.ErrorCode_OVERLOADED {
+            overloaded++
+        }
+    }
+    if answered != size || overloaded != flood-size {
+        t.Errorf("%d answered and %d overloaded, want %d answered and %d overloaded",
+            answered, overloaded, size, flood-size)
+    }
+}
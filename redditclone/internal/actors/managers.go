@@ -0,0 +1,173 @@
+// internal/actors/managers.go
+package actors
+
+import (
+    "errors"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/cluster"
+    "go.opentelemetry.io/otel/trace"
+    "redditclone/pkg/clock"
+)
+
+// Names the managers are spawned under, so remote clients can address them
+// with actor.NewPID(engineAddress, name)
+const (
+    UserManagerName      = "user-manager"
+    SubredditManagerName = "subreddit-manager"
+    PostManagerName      = "post-manager"
+    CommentManagerName   = "comment-manager"
+    MessageManagerName   = "message-manager"
+)
+
+// ManagerConfig holds the settings shared by the managers.
+type ManagerConfig struct {
+    // IdempotencyWindow is how long a manager remembers the response to a
+    // create request with an idempotency key. Zero ignores keys. Managers
+    // keep responses in memory only, so they are forgotten on restart and
+    // failover.
+    IdempotencyWindow time.Duration
+
+    // Clock is where managers and grains get the time for timestamps,
+    // idempotency expiry and passivation.
+    // Defaults to the system clock.
+    Clock clock.Clock
+
+    // Shards is how many grains the post and comment managers are each
+    // split into, behind a router on each engine that hashes by post.
+    // Defaults to one.
+    Shards int
+
+    // Cluster hosts the grains; StartCluster makes it. Store and
+    // PassivateAfter are read by StartCluster: grains save their state to
+    // Store as it changes, and users and subreddits are deactivated when
+    // they go PassivateAfter without a message. Zero never passivates
+    // them.
+    Cluster        *cluster.Cluster
+    Store          GrainStore
+    PassivateAfter time.Duration
+
+    // Supervision limits how often a failing manager is restarted.
+    Supervision SupervisionConfig
+
+    // Mailboxes bounds each manager's mailbox, by manager name; one not
+    // listed is unbounded. MailboxStats counts what waits in them and
+    // what they turn away, and what waits in the grains', by kind.
+    Mailboxes    map[string]MailboxConfig
+    MailboxStats *MailboxStats
+
+    // Observer, if set, hears about every request the managers, the post
+    // and comment shards and the user and subreddit grains answer.
+    Observer RequestObserver
+
+    // Tracer, if set, traces every request the managers, shards and grains
+    // answer, joining the trace of the client's span when the request
+    // carries its context.
+    Tracer trace.Tracer
+}
+
+func DefaultManagerConfig() ManagerConfig {
+    return ManagerConfig{
+        IdempotencyWindow: 5 * time.Minute,
+        Clock:             clock.Real{},
+        Shards:            1,
+        Store:             NewMemoryStore(),
+        PassivateAfter:    2 * time.Minute,
+        Supervision:       DefaultSupervisionConfig(),
+        Mailboxes: map[string]MailboxConfig{
+            UserManagerName:      {Size: 10000},
+            SubredditManagerName: {Size: 10000},
+            PostManagerName:      {Size: 10000},
+            CommentManagerName:   {Size: 10000},
+            MessageManagerName:   {Size: 10000},
+        },
+        MailboxStats: NewMailboxStats(),
+    }
+}
+
+// managerOptions gives the named manager's actors, its shards included,
+// mailboxes bounded as Mailboxes says, and reports the requests they
+// answer to Observer and Tracer as actorName.
+func (config ManagerConfig) managerOptions(system *actor.ActorSystem, manager, actorName string) []actor.PropsOption {
+    mailbox := actor.WithMailbox(newMailbox(system, manager, config.Mailboxes[manager], config.MailboxStats, config.Tracer != nil))
+    return append(observed(actorName, config.Observer, config.Tracer), mailbox)
+}
+
+// grainOptions reports the requests the user and subreddit grains answer
+// to Observer and Tracer, and counts what waits in their mailboxes under
+// the kind's name. Grains' mailboxes are unbounded, as their managers'
+// turn requests away before they get that far.
+func (config ManagerConfig) grainOptions(system *actor.ActorSystem, kind string) []actor.PropsOption {
+    mailbox := actor.WithMailbox(newMailbox(system, kind, MailboxConfig{}, config.MailboxStats, config.Tracer != nil))
+    return append(observed(kind, config.Observer, config.Tracer), mailbox)
+}
+
+type Managers struct {
+    UserManager      *actor.PID
+    SubredditManager *actor.PID
+    PostManager      *actor.PID
+    CommentManager   *actor.PID
+    MessageManager   *actor.PID
+}
+
+// SpawnManagers spawns the five manager actors under their well-known names.
+// They all pass requests on to grains in config.Cluster: the user,
+// subreddit and message managers to the grain of the user or subreddit a
+// request is about, and the post and comment managers, which are routers,
+// to the shard its post hashes to. Each is supervised as config.Supervision
+// says, and turns requests away once config.Mailboxes says it is full.
+func SpawnManagers(root *actor.RootContext, config ManagerConfig) (*Managers, error) {
+    m := &Managers{}
+    var err error
+    if config.Cluster == nil {
+        return nil, errors.New("managers need a cluster for their grains")
+    }
+    if config.Clock == nil {
+        config.Clock = clock.Real{}
+    }
+    if config.MailboxStats == nil {
+        config.MailboxStats = NewMailboxStats()
+    }
+    supervised := actor.WithGuardian(newManagerStrategy(config.Supervision))
+    system := root.ActorSystem()
+    options := func(manager string) []actor.PropsOption {
+        return append(config.managerOptions(system, manager, manager), supervised)
+    }
+
+    m.UserManager, err = root.SpawnNamed(actor.PropsFromProducer(func() actor.Actor {
+        return NewUserManagerActor(config.Cluster, config.IdempotencyWindow, config.Clock)
+    }, options(UserManagerName)...), UserManagerName)
+    if err != nil {
+        return nil, err
+    }
+
+    m.SubredditManager, err = root.SpawnNamed(actor.PropsFromProducer(func() actor.Actor {
+        return NewSubRedditManagerActor(config.Cluster, config.IdempotencyWindow, config.Clock)
+    }, options(SubredditManagerName)...), SubredditManagerName)
+    if err != nil {
+        return nil, err
+    }
+
+    m.PostManager, err = root.SpawnNamed(actor.PropsFromProducer(func() actor.Actor {
+        return NewShardRouterActor(config.Cluster, postRing(config.Shards))
+    }, options(PostManagerName)...), PostManagerName)
+    if err != nil {
+        return nil, err
+    }
+
+    m.CommentManager, err = root.SpawnNamed(actor.PropsFromProducer(func() actor.Actor {
+        return NewShardRouterActor(config.Cluster, commentRing(config.Shards))
+    }, options(CommentManagerName)...), CommentManagerName)
+    if err != nil {
+        return nil, err
+    }
+
+    m.MessageManager, err = root.SpawnNamed(actor.PropsFromProducer(func() actor.Actor {
+        return NewMessageManagerActor(config.Cluster, config.IdempotencyWindow, config.Clock)
+    }, options(MessageManagerName)...), MessageManagerName)
+    if err != nil {
+        return nil, err
+    }
+
+    return m, nil
+}
@@ -2,36 +2,42 @@
 package actors
 
 import (
-    "fmt"
     "github.com/asynkron/protoactor-go/actor"
     "redditclone/internal/messages"
-    "google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// Receive passes direct messages on to the grain of the user whose inbox
+// they belong in, once the sender's grain has said the sender exists, so
+// an unknown sender can't fill a real user's inbox.
 func (state *MessageManagerActor) Receive(context actor.Context) {
     switch msg := context.Message().(type) {
     case *messages.SendDirectMessageMsg:
-        messageID := fmt.Sprintf("msg_%d", len(state.Messages))
-        newMessage := &messages.DirectMessage{
-            Id:         messageID,
-            FromUserId: msg.FromUserId,
-            ToUserId:   msg.ToUserId,
-            Content:    msg.Content,
-            Timestamp:  timestamppb.Now(),
+        if msg.FromUserId == "" {
+            context.Respond(invalidArgument("from_user_id", "must not be empty"))
+            return
+        }
+        if msg.ToUserId == "" {
+            context.Respond(invalidArgument("to_user_id", "must not be empty"))
+            return
         }
 
-        // Add message to recipient's inbox
-        if _, exists := state.Messages[msg.ToUserId]; !exists {
-            state.Messages[msg.ToUserId] = make([]*messages.DirectMessage, 0)
+        if !state.Idempotency.begin(context, msg.IdempotencyKey) {
+            return
         }
-        state.Messages[msg.ToUserId] = append(state.Messages[msg.ToUserId], newMessage)
 
-        context.Respond(&messages.OperationResponse{
-            Success: true,
-            Id:      messageID,
-            Result: &messages.OperationResponse_Message{
-                Message: newMessage,
-            },
+        sender := requestGrain(context, state.Cluster, UserKind, msg.FromUserId, &messages.GetUserMsg{UserId: msg.FromUserId})
+        context.ReenterAfter(sender, func(res interface{}, err error) {
+            if response := asResponse(res, err); !response.Success {
+                state.Idempotency.finish(context, msg.IdempotencyKey, response)
+                return
+            }
+            future := requestGrain(context, state.Cluster, UserKind, msg.ToUserId, msg)
+            context.ReenterAfter(future, func(res interface{}, err error) {
+                state.Idempotency.finish(context, msg.IdempotencyKey, asResponse(res, err))
+            })
         })
+
+    case *messages.GetDirectMessagesMsg:
+        forwardToGrain(context, state.Cluster, UserKind, msg.UserId, msg)
     }
-}
\ No newline at end of file
+}
@@ -0,0 +1,161 @@
+// internal/actors/observe.go
+package actors
+
+import (
+    "fmt"
+    "strconv"
+    "sync"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/trace"
+    "redditclone/internal/messages"
+    "redditclone/internal/tracing"
+    "google.golang.org/protobuf/proto"
+)
+
+// RequestObserver hears about every request a manager, shard or grain
+// answers: who answered it, the request's message type, the response's
+// error code, and how long it took from arriving in the actor to being
+// answered, including any time spent waiting on grains.
+type RequestObserver interface {
+    ObserveRequest(actorName, message string, code messages.ErrorCode, took time.Duration)
+}
+
+// pendingTTL is how long a request is waited on for its answer. One not
+// answered by then, or turned away by its mailbox, is forgotten, and its
+// span never ends.
+const pendingTTL = time.Minute
+
+type pendingRequest struct {
+    message string
+    arrived time.Time
+    span    trace.Span // nil unless traced
+}
+
+// queuedAtHeader is the header a bounded mailbox stamps on a traced request
+// with when it was queued, in Unix nanoseconds, so its span can show how
+// long it waited.
+const queuedAtHeader = "redditclone-queued-at"
+
+// observed tells observer about the requests actors with these props
+// answer, as actorName, and traces each one with tracer as a span from its
+// arrival to its answer. A request that carries a span's context in its
+// headers, as those from a tracing client do, is traced as that span's
+// child, and so are the grain requests made while handling it. A request
+// is paired with its answer by who is waiting on it: a future, for
+// requests from clients and from other actors alike. A request the actor
+// fails on is observed as answered INTERNAL, as the failure is answered
+// from outside the actor.
+func observed(actorName string, observer RequestObserver, tracer trace.Tracer) []actor.PropsOption {
+    if observer == nil && tracer == nil {
+        return nil
+    }
+    var (
+        pending   sync.Map // of *pendingRequest, by the waiting PID
+        mu        sync.Mutex
+        lastSweep = time.Now()
+    )
+    sweep := func(now time.Time) {
+        mu.Lock()
+        defer mu.Unlock()
+        if now.Sub(lastSweep) < pendingTTL {
+            return
+        }
+        lastSweep = now
+        pending.Range(func(key, value interface{}) bool {
+            if now.Sub(value.(*pendingRequest).arrived) > pendingTTL {
+                pending.Delete(key)
+            }
+            return true
+        })
+    }
+
+    answered := func(waiting *actor.PID, code messages.ErrorCode) {
+        value, ok := pending.LoadAndDelete(waiting.String())
+        if !ok {
+            return
+        }
+        request := value.(*pendingRequest)
+        if observer != nil {
+            observer.ObserveRequest(actorName, request.message, code, time.Since(request.arrived))
+        }
+        if request.span != nil {
+            endSpan(request.span, code)
+        }
+    }
+
+    receiver := actor.WithReceiverMiddleware(func(next actor.ReceiverFunc) actor.ReceiverFunc {
+        return func(c actor.ReceiverContext, envelope *actor.MessageEnvelope) {
+            if envelope.Sender != nil {
+                now := time.Now()
+                request := &pendingRequest{
+                    message: messageName(envelope.Message),
+                    arrived: now,
+                }
+                if tracer != nil {
+                    request.span = startSpan(tracer, c.Self(), actorName, request.message, envelope, now)
+                }
+                pending.Store(envelope.Sender.String(), request)
+                sweep(now)
+                defer func() {
+                    if reason := recover(); reason != nil {
+                        answered(envelope.Sender, messages.ErrorCode_INTERNAL)
+                        panic(reason)
+                    }
+                }()
+            }
+            next(c, envelope)
+        }
+    })
+    sender := actor.WithSenderMiddleware(func(next actor.SenderFunc) actor.SenderFunc {
+        return func(c actor.SenderContext, target *actor.PID, envelope *actor.MessageEnvelope) {
+            code := messages.ErrorCode_OK
+            if response, ok := envelope.Message.(*messages.OperationResponse); ok {
+                code = response.Code
+            }
+            answered(target, code)
+            next(c, target, envelope)
+        }
+    })
+    return []actor.PropsOption{receiver, sender}
+}
+
+// startSpan starts the span of a request arriving at self, from when it
+// was queued if its mailbox says, with a child span for the wait. The
+// request's headers are switched to the new span's context, so requests
+// made while handling it, continuations included, are its children.
+func startSpan(tracer trace.Tracer, self *actor.PID, actorName, message string, envelope *actor.MessageEnvelope, now time.Time) trace.Span {
+    start := now
+    if nanos, err := strconv.ParseInt(envelope.GetHeader(queuedAtHeader), 10, 64); err == nil {
+        start = time.Unix(0, nanos)
+    }
+    ctx, span := tracer.Start(tracing.Extract(envelope.Header), actorName+"/"+message,
+        trace.WithSpanKind(trace.SpanKindServer),
+        trace.WithTimestamp(start),
+        trace.WithAttributes(attribute.String("actor", self.Id), attribute.String("address", self.Address)))
+    if !start.Equal(now) {
+        _, queued := tracer.Start(ctx, actorName+"/queued", trace.WithTimestamp(start))
+        queued.End(trace.WithTimestamp(now))
+    }
+    tracing.Inject(ctx, envelope)
+    return span
+}
+
+func endSpan(span trace.Span, code messages.ErrorCode) {
+    span.SetAttributes(attribute.String("code", code.String()))
+    if code != messages.ErrorCode_OK {
+        span.SetStatus(codes.Error, code.String())
+    }
+    span.End()
+}
+
+// messageName is a message's proto name without the package, e.g.
+// CreatePostMsg.
+func messageName(message interface{}) string {
+    if m, ok := message.(proto.Message); ok {
+        return string(m.ProtoReflect().Descriptor().Name())
+    }
+    return fmt.Sprintf("%T", message)
+}
@@ -0,0 +1,67 @@
+// internal/actors/passivate.go
+package actors
+
+import (
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "redditclone/pkg/clock"
+)
+
+// passivation stops a grain that has gone a while without a message, as
+// SetReceiveTimeout would, but by the grain's clock, so a test holding a
+// clock.Fake decides when grains passivate.
+//
+// One timer runs per grain rather than one per message: when it goes off
+// the grain checks how long it has really been idle and, if a message came
+// in since, sets it again for the rest of the time.
+type passivation struct {
+    after  time.Duration
+    clock  clock.Clock
+    active time.Time // when the grain last got a message
+    timer  clock.Timer
+}
+
+// passivate is a grain's passivation timer going off.
+type passivate struct{}
+
+// newPassivation returns a passivation after the given idle time, as clock
+// tells it. Zero never passivates.
+func newPassivation(after time.Duration, clock clock.Clock) *passivation {
+    return &passivation{after: after, clock: clock}
+}
+
+// receive notes that the grain got a message, and stops the grain if the
+// message is its timer going off after the full idle time. It returns true
+// for the timer's messages, which need nothing more.
+func (p *passivation) receive(context actor.Context) bool {
+    if p.after <= 0 {
+        return false
+    }
+
+    switch context.Message().(type) {
+    case *actor.Started:
+        p.active = p.clock.Now()
+        p.start(context, p.after)
+    case *actor.Stopping, *actor.Restarting:
+        if p.timer != nil {
+            p.timer.Stop()
+        }
+    case *passivate:
+        if idle := p.clock.Now().Sub(p.active); idle >= p.after {
+            context.Stop(context.Self())
+        } else {
+            p.start(context, p.after-idle)
+        }
+        return true
+    default:
+        p.active = p.clock.Now()
+    }
+    return false
+}
+
+func (p *passivation) start(context actor.Context, after time.Duration) {
+    system, self := context.ActorSystem(), context.Self()
+    p.timer = p.clock.AfterFunc(after, func() {
+        system.Root.Send(self, &passivate{})
+    })
+}
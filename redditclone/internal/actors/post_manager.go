@@ -2,61 +2,337 @@
 package actors
 
 import (
-    "fmt"
-    //"time"
+    "log/slog"
+    "sort"
+    "time"
     "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/cluster"
     "redditclone/internal/messages"
+    "redditclone/pkg/clock"
+    "google.golang.org/protobuf/proto"
     "google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// postStoreKind is what post shards save each post under, by its ID.
+const postStoreKind = "post"
+
+func newPostShardKind(store GrainStore, idempotencyWindow time.Duration, clock clock.Clock, ring *shardRing, options []actor.PropsOption) *cluster.Kind {
+    return cluster.NewKind(PostShardKind, actor.PropsFromProducer(func() actor.Actor {
+        return NewPostManagerActor(store, idempotencyWindow, clock, ring)
+    }, append(options, recoverGrain(PostShardKind))...))
+}
+
+// Receive handles the requests the post manager's router sends this shard.
+// A shard is a grain so it can move to another member when the one it was
+// on fails: it loads the posts it owns when activated, and saves a post
+// before answering any request that changed it.
 func (state *PostManagerActor) Receive(context actor.Context) {
     switch msg := context.Message().(type) {
-    case *actor.Started:
-        // Store the user manager PID for karma updates
-        state.UserManager = context.Parent()
+    case *cluster.ClusterInit:
+        state.Identity = msg.Identity.Identity
+        state.Cluster = msg.Cluster
+        state.LoadErr = state.load()
 
     case *messages.CreatePostMsg:
-        postID := fmt.Sprintf("post_%d", len(state.Posts)+1)
-        newPost := &messages.Post{
-            Id:        postID,
-            Title:     msg.Title,
-            Content:   msg.Content,
-            AuthorId:  msg.AuthorId,
-            Subreddit: msg.Subreddit,
-            Upvotes:   0,
-            Downvotes: 0,
-            Timestamp: timestamppb.Now(),
+        if msg.Title == "" {
+            context.Respond(invalidArgument("title", "must not be empty"))
+            return
+        }
+        if !state.loaded(context) {
+            return
         }
-        state.Posts[postID] = newPost
 
-        context.Respond(&messages.OperationResponse{
-            Success: true,
-            Id:      postID,
-            Result: &messages.OperationResponse_Post{
-                Post: newPost,
-            },
+        if !state.Idempotency.begin(context, msg.IdempotencyKey) {
+            return
+        }
+
+        // The subreddit lists its posts, so have it index this one before
+        // storing it; that fails if there is no such subreddit
+        postID := state.Ring.nextID(state.Identity, "post", &state.LastID)
+        future := requestGrain(context, state.Cluster, SubredditKind, msg.Subreddit, &messages.IndexPostMsg{
+            Subreddit: msg.Subreddit,
+            PostId:    postID,
+        })
+
+        context.ReenterAfter(future, func(res interface{}, err error) {
+            if response := asResponse(res, err); !response.Success {
+                state.Idempotency.finish(context, msg.IdempotencyKey, response)
+                return
+            }
+
+            newPost := &messages.Post{
+                Id:        postID,
+                Title:     msg.Title,
+                Content:   msg.Content,
+                AuthorId:  msg.AuthorId,
+                Subreddit: msg.Subreddit,
+                Upvotes:   0,
+                Downvotes: 0,
+                Timestamp: timestamppb.New(state.Clock.Now()),
+            }
+            state.Posts[postID] = newPost
+            state.Votes[postID] = make(map[string]bool)
+            if !state.save(context, postID) {
+                // The shard is stopping without the post, so take it back
+                // out of the subreddit's list
+                requestGrain(context, state.Cluster, SubredditKind, msg.Subreddit, &messages.UnindexPostMsg{
+                    Subreddit: msg.Subreddit,
+                    PostId:    postID,
+                })
+                return
+            }
+
+            state.Idempotency.finish(context, msg.IdempotencyKey, &messages.OperationResponse{
+                Success: true,
+                Id:      postID,
+                Result: &messages.OperationResponse_Post{
+                    Post: proto.Clone(newPost).(*messages.Post),
+                },
+            })
         })
 
     case *messages.VoteMsg:
+        if msg.UserId == "" {
+            context.Respond(invalidArgument("user_id", "must not be empty"))
+            return
+        }
+        if !state.loaded(context) {
+            return
+        }
+
         if post, exists := state.Posts[msg.ItemId]; exists {
-            if msg.IsUpvote {
-                post.Upvotes++
-            } else {
-                post.Downvotes++
-            }
-            
-            // Update author's karma
-            context.Request(state.UserManager, &messages.VoteMsg{
-                UserId: post.AuthorId,
-                IsUpvote: msg.IsUpvote,
-            })
+            upDelta, downDelta := applyVote(state.Votes[post.Id], msg.UserId, msg.IsUpvote)
+            post.Upvotes += upDelta
+            post.Downvotes += downDelta
+            if upDelta != 0 || downDelta != 0 {
+                if !state.save(context, post.Id) {
+                    return
+                }
+            }
 
-            context.Respond(&messages.OperationResponse{Success: true})
+            answerVote(context, state.Cluster, post.AuthorId, msg, state.Clock, &messages.OperationResponse{
+                Success: true,
+                Id:      post.Id,
+                Result: &messages.OperationResponse_Post{
+                    Post: proto.Clone(post).(*messages.Post),
+                },
+            })
         } else {
+            context.Respond(notFound("item_id", "post", msg.ItemId))
+        }
+
+    case *messages.GetPostMsg:
+        if !state.loaded(context) {
+            return
+        }
+        if post, exists := state.Posts[msg.PostId]; exists {
             context.Respond(&messages.OperationResponse{
-                Success: false,
-                Error: "post not found",
+                Success: true,
+                Id:      post.Id,
+                Result: &messages.OperationResponse_Post{
+                    Post: proto.Clone(post).(*messages.Post),
+                },
             })
+        } else {
+            context.Respond(notFound("post_id", "post", msg.PostId))
+        }
+
+    case *messages.GetFeedMsg:
+        if !state.loaded(context) {
+            return
+        }
+        future := requestGrain(context, state.Cluster, UserKind, msg.UserId, &messages.GetSubscriptionsMsg{
+            UserId: msg.UserId,
+        })
+
+        context.ReenterAfter(future, func(res interface{}, err error) {
+            response := asResponse(res, err)
+            if !response.Success {
+                context.Respond(response)
+                return
+            }
+
+            subreddits := make([]string, 0, len(response.Subreddits))
+            for _, subreddit := range response.Subreddits {
+                subreddits = append(subreddits, subreddit.Id)
+            }
+            state.gatherFeed(context, subreddits)
+        })
+
+    case *messages.FeedPartMsg:
+        if !state.loaded(context) {
+            return
+        }
+        context.Respond(&messages.OperationResponse{
+            Success: true,
+            Posts:   state.feedPosts(msg.Subreddits),
+        })
+    }
+}
+
+// gatherFeed answers a feed request with this shard's posts in the
+// subscribed subreddits and every other shard's. A shard that doesn't
+// answer fails the whole feed rather than leave a hole in it.
+func (state *PostManagerActor) gatherFeed(context actor.Context, subreddits []string) {
+    feed := state.feedPosts(subreddits)
+    pending := len(state.Ring.shards) - 1
+    failed := false
+    respond := func() {
+        sortFeed(feed)
+        context.Respond(&messages.OperationResponse{
+            Success: true,
+            Posts:   feed,
+        })
+    }
+    if pending == 0 {
+        respond()
+        return
+    }
+
+    for _, shard := range state.Ring.shards {
+        if shard == state.Identity {
+            continue
         }
+        future := requestGrain(context, state.Cluster, PostShardKind, shard, &messages.FeedPartMsg{
+            Subreddits: subreddits,
+        })
+        context.ReenterAfter(future, func(res interface{}, err error) {
+            pending--
+            if part := asResponse(res, err); part.Success {
+                feed = append(feed, part.Posts...)
+            } else if !failed {
+                failed = true
+                context.Respond(part)
+            }
+            if pending == 0 && !failed {
+                respond()
+            }
+        })
+    }
+}
+
+func (state *PostManagerActor) feedPosts(subreddits []string) []*messages.Post {
+    subscribed := make(map[string]bool, len(subreddits))
+    for _, subreddit := range subreddits {
+        subscribed[subreddit] = true
+    }
+    feed := make([]*messages.Post, 0)
+    for _, post := range state.Posts {
+        if subscribed[post.Subreddit] {
+            feed = append(feed, proto.Clone(post).(*messages.Post))
+        }
+    }
+    return feed
+}
+
+// load claims the shard, fencing off any earlier activation as a user
+// grain does, reads back the posts it owns, and carries on numbering after
+// the highest of them.
+func (state *PostManagerActor) load() error {
+    lease, err := state.Store.Claim(PostShardKind, state.Identity)
+    if err != nil {
+        return err
+    }
+    state.Lease = lease
+
+    postIDs, err := state.Store.Keys(postStoreKind)
+    if err != nil {
+        return err
+    }
+    for _, postID := range postIDs {
+        if !state.Ring.owns(state.Identity, postID) {
+            continue
+        }
+        stored := &messages.StoredPost{}
+        if _, err := state.Store.Load(postStoreKind, postID, stored); err != nil {
+            return err
+        }
+        state.Posts[postID] = stored.Post
+        state.Votes[postID] = stored.Votes
+        if state.Votes[postID] == nil {
+            state.Votes[postID] = make(map[string]bool)
+        }
+        if n := idNumber(postID); n > state.LastID {
+            state.LastID = n
+        }
+    }
+    return nil
+}
+
+// loaded answers with an error if the shard's posts couldn't be loaded,
+// and stops it so the next request activates it afresh.
+func (state *PostManagerActor) loaded(context actor.Context) bool {
+    if state.LoadErr == nil {
+        return true
+    }
+    context.Respond(internalError("%s could not be loaded: %v", state.Identity, state.LoadErr))
+    context.Stop(context.Self())
+    return false
+}
+
+// save writes a post through to the store. If that fails it answers with
+// an error and stops the shard, which drops the unsaved change: the next
+// request activates it afresh from what was saved.
+func (state *PostManagerActor) save(context actor.Context, postID string) bool {
+    err := state.Store.Save(state.Lease, postStoreKind, postID, &messages.StoredPost{
+        Post:  state.Posts[postID],
+        Votes: state.Votes[postID],
+    })
+    if err == nil {
+        return true
+    }
+    context.Respond(internalError("post %s could not be saved: %v", postID, err))
+    context.Stop(context.Self())
+    return false
+}
+
+// sortFeed orders posts by score, newest first among equal scores.
+func sortFeed(posts []*messages.Post) {
+    sort.Slice(posts, func(i, j int) bool {
+        scoreI := posts[i].Upvotes - posts[i].Downvotes
+        scoreJ := posts[j].Upvotes - posts[j].Downvotes
+        if scoreI != scoreJ {
+            return scoreI > scoreJ
+        }
+        return posts[i].Timestamp.AsTime().After(posts[j].Timestamp.AsTime())
+    })
+}
+
+// answerVote answers a vote on an item by authorID with response once the
+// author's karma is updated, so the voter reads it back. The vote stands
+// whatever the update's outcome: a failed update is logged, and a repeated
+// vote updates the karma again.
+func answerVote(context actor.Context, c *cluster.Cluster, authorID string, msg *messages.VoteMsg, clock clock.Clock, response *messages.OperationResponse) {
+    vote := int32(1)
+    if !msg.IsUpvote {
+        vote = -1
+    }
+    future := requestGrain(context, c, UserKind, authorID, &messages.UpdateKarmaMsg{
+        UserId:  authorID,
+        Vote:    vote,
+        ItemId:  msg.ItemId,
+        VoterId: msg.UserId,
+        Cast:    timestamppb.New(clock.Now()),
+    })
+    context.ReenterAfter(future, func(res interface{}, err error) {
+        if karma := asResponse(res, err); !karma.Success {
+            context.Logger().Warn("Failed to update karma",
+                slog.String("author", authorID),
+                slog.String("item", msg.ItemId),
+                slog.String("error", karma.Error))
+        }
+        context.Respond(response)
+    })
+}
+
+// asResponse turns the outcome of a request to another manager into the
+// OperationResponse to pass on.
+func asResponse(res interface{}, err error) *messages.OperationResponse {
+    if err != nil {
+        return internalError("request failed: %v", err)
+    }
+    if response, ok := res.(*messages.OperationResponse); ok {
+        return response
     }
-}
\ No newline at end of file
+    return internalError("unexpected response %T", res)
+}
@@ -0,0 +1,108 @@
+// internal/actors/shards.go
+package actors
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/cluster"
+    "github.com/asynkron/protoactor-go/router"
+    "github.com/serialx/hashring"
+)
+
+// Cluster kinds of the post and comment shards. A shard's identity is its
+// manager's name and its number, e.g. post-manager-0.
+const (
+    PostShardKind    = "post-shard"
+    CommentShardKind = "comment-shard"
+)
+
+// shardRing is the consistent-hash ring a manager's shards sit on. Every
+// engine builds the same ring from the shard count, so they all send a key
+// to the same shard grain, wherever in the cluster it runs, and a shard can
+// tell which keys are its own.
+type shardRing struct {
+    ring   *hashring.HashRing
+    kind   string
+    shards []string
+}
+
+func newShardRing(kind, name string, count int) *shardRing {
+    if count < 1 {
+        count = 1
+    }
+    shards := make([]string, count)
+    for i := range shards {
+        shards[i] = fmt.Sprintf("%s-%d", name, i)
+    }
+    return &shardRing{ring: hashring.New(shards), kind: kind, shards: shards}
+}
+
+func postRing(count int) *shardRing {
+    return newShardRing(PostShardKind, PostManagerName, count)
+}
+
+func commentRing(count int) *shardRing {
+    return newShardRing(CommentShardKind, CommentManagerName, count)
+}
+
+// shard is the identity of the shard that owns key.
+func (r *shardRing) shard(key string) string {
+    shard, _ := r.ring.GetNode(key)
+    return shard
+}
+
+// owns reports whether key belongs to shard.
+func (r *shardRing) owns(shard, key string) bool {
+    return r.shard(key) == shard
+}
+
+// nextID counts on from *last to the next ID with prefix that belongs to
+// shard. With one shard that is simply the next number; with n it takes
+// about n tries, each hashing one short string.
+func (r *shardRing) nextID(shard, prefix string, last *int) string {
+    for {
+        *last++
+        if id := fmt.Sprintf("%s_%d", prefix, *last); r.owns(shard, id) {
+            return id
+        }
+    }
+}
+
+// idNumber is the number nextID gave an ID, or 0 for an ID it didn't give.
+func idNumber(id string) int {
+    n, err := strconv.Atoi(id[strings.LastIndex(id, "_")+1:])
+    if err != nil {
+        return 0
+    }
+    return n
+}
+
+// ShardRouterActor stands in for a sharded manager on each engine: clients
+// address it as if it were the manager, and it passes every request on to
+// the shard grain the request's router.Hasher key belongs to.
+//
+// It does the job of router.NewConsistentHashGroup, on the same hash ring,
+// but over shard grain identities instead of PIDs. That router hashes each
+// routee's address with its ID, so engines at different addresses would
+// send a key to different shards, and it needs fixed PIDs while shard
+// grains move between cluster members. Its process also routes messages
+// without queueing them, past the manager's mailbox and middleware.
+type ShardRouterActor struct {
+    Cluster *cluster.Cluster
+    Ring *shardRing
+}
+
+func NewShardRouterActor(c *cluster.Cluster, ring *shardRing) *ShardRouterActor {
+    return &ShardRouterActor{
+        Cluster: c,
+        Ring: ring,
+    }
+}
+
+func (state *ShardRouterActor) Receive(context actor.Context) {
+    if msg, ok := context.Message().(router.Hasher); ok {
+        forwardToGrain(context, state.Cluster, state.Ring.kind, state.Ring.shard(msg.Hash()), msg)
+    }
+}
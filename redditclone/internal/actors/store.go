@@ -0,0 +1,281 @@
+// internal/actors/store.go
+package actors
+
+import (
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "io"
+    "net/url"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+    "syscall"
+    "redditclone/internal/messages"
+    "google.golang.org/protobuf/proto"
+)
+
+// ErrFenced is returned by GrainStore.Save when the grain has been claimed
+// by a newer activation.
+var ErrFenced = errors.New("grain was claimed by a newer activation")
+
+// GrainStore keeps the state of grains, by kind and identity. Engines
+// sharing a cluster must share a store, since a grain may next activate on
+// another member, for instance when the one it was on fails.
+//
+// While members disagree about who owns a grain, for a moment after one
+// joins or fails, the grain can be active on two of them at once. So an
+// activation claims its grain before loading, and saves with the Lease it
+// got: once a newer activation has claimed the grain, the older one's
+// saves fail with ErrFenced instead of overwriting what the newer one
+// loaded.
+type GrainStore interface {
+    // Claim fences off every earlier activation of the grain.
+    Claim(kind, identity string) (Lease, error)
+
+    // Load fills state from what was saved and reports whether there was
+    // anything.
+    Load(kind, identity string, state proto.Message) (bool, error)
+
+    // Save stores state under kind and identity, which needn't be the
+    // grain's own: a shard saves each of its posts separately.
+    Save(lease Lease, kind, identity string, state proto.Message) error
+
+    // Keys lists the identities saved under kind, in no particular order.
+    Keys(kind string) ([]string, error)
+}
+
+// A Lease is an activation's claim on its grain.
+type Lease struct {
+    Kind     string
+    Identity string
+    Epoch    int64
+}
+
+type memoryStore struct {
+    mu     sync.Mutex
+    states map[string][]byte
+    epochs map[string]int64
+}
+
+// NewMemoryStore keeps grain state in memory, which is enough for a cluster
+// within one process.
+func NewMemoryStore() GrainStore {
+    return &memoryStore{
+        states: make(map[string][]byte),
+        epochs: make(map[string]int64),
+    }
+}
+
+func (s *memoryStore) Claim(kind, identity string) (Lease, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.epochs[kind+"/"+identity]++
+    return Lease{Kind: kind, Identity: identity, Epoch: s.epochs[kind+"/"+identity]}, nil
+}
+
+func (s *memoryStore) Load(kind, identity string, state proto.Message) (bool, error) {
+    s.mu.Lock()
+    data, ok := s.states[kind+"/"+identity]
+    s.mu.Unlock()
+    if !ok {
+        return false, nil
+    }
+    return true, proto.Unmarshal(data, state)
+}
+
+func (s *memoryStore) Save(lease Lease, kind, identity string, state proto.Message) error {
+    data, err := proto.Marshal(state)
+    if err != nil {
+        return err
+    }
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.epochs[lease.Kind+"/"+lease.Identity] != lease.Epoch {
+        return ErrFenced
+    }
+    s.states[kind+"/"+identity] = data
+    return nil
+}
+
+func (s *memoryStore) Keys(kind string) ([]string, error) {
+    prefix := kind + "/"
+    keys := make([]string, 0)
+    s.mu.Lock()
+    for key := range s.states {
+        if strings.HasPrefix(key, prefix) {
+            keys = append(keys, key[len(prefix):])
+        }
+    }
+    s.mu.Unlock()
+    return keys, nil
+}
+
+type fileStore struct {
+    dir string
+}
+
+// NewFileStore keeps grain state in files under dir, one directory per
+// kind, so engine processes on one machine can share it. Each grain's
+// epoch is kept in a file next to its state, which is locked while it is
+// claimed or saved under, so claims and saves from different processes
+// take turns.
+func NewFileStore(dir string) (GrainStore, error) {
+    if err := os.MkdirAll(dir, 0o755); err != nil {
+        return nil, fmt.Errorf("grain store: %w", err)
+    }
+    return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) path(kind, identity string) string {
+    return filepath.Join(s.dir, kind, url.PathEscape(identity))
+}
+
+// epoch opens the grain's epoch file locked with how, shared for saves and
+// exclusive for claims, and reads the epoch in it. Closing the file
+// unlocks it.
+func (s *fileStore) epoch(kind, identity string, how int) (*os.File, int64, error) {
+    path := filepath.Join(s.dir, kind, ".epoch-"+url.PathEscape(identity))
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return nil, 0, err
+    }
+    file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+    if err != nil {
+        return nil, 0, err
+    }
+    if err := syscall.Flock(int(file.Fd()), how); err != nil {
+        file.Close()
+        return nil, 0, err
+    }
+    var epoch [8]byte
+    if _, err := io.ReadFull(file, epoch[:]); err != nil && err != io.EOF {
+        file.Close()
+        return nil, 0, err
+    }
+    return file, int64(binary.BigEndian.Uint64(epoch[:])), nil
+}
+
+func (s *fileStore) Claim(kind, identity string) (Lease, error) {
+    file, epoch, err := s.epoch(kind, identity, syscall.LOCK_EX)
+    if err != nil {
+        return Lease{}, err
+    }
+    defer file.Close()
+    epoch++
+    if _, err := file.WriteAt(binary.BigEndian.AppendUint64(nil, uint64(epoch)), 0); err != nil {
+        return Lease{}, err
+    }
+    return Lease{Kind: kind, Identity: identity, Epoch: epoch}, nil
+}
+
+func (s *fileStore) Load(kind, identity string, state proto.Message) (bool, error) {
+    data, err := os.ReadFile(s.path(kind, identity))
+    if os.IsNotExist(err) {
+        return false, nil
+    }
+    if err != nil {
+        return false, err
+    }
+    return true, proto.Unmarshal(data, state)
+}
+
+// Save writes to a temporary file and renames it into place, so a reader
+// never sees half a state.
+func (s *fileStore) Save(lease Lease, kind, identity string, state proto.Message) error {
+    data, err := proto.Marshal(state)
+    if err != nil {
+        return err
+    }
+    file, epoch, err := s.epoch(lease.Kind, lease.Identity, syscall.LOCK_SH)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+    if epoch != lease.Epoch {
+        return ErrFenced
+    }
+
+    path := s.path(kind, identity)
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return err
+    }
+    tmp, err := os.CreateTemp(filepath.Dir(path), ".save-*")
+    if err != nil {
+        return err
+    }
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        os.Remove(tmp.Name())
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        os.Remove(tmp.Name())
+        return err
+    }
+    return os.Rename(tmp.Name(), path)
+}
+
+// Keys skips the temporary and epoch files, whose names start with a dot.
+func (s *fileStore) Keys(kind string) ([]string, error) {
+    entries, err := os.ReadDir(filepath.Join(s.dir, kind))
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    keys := make([]string, 0, len(entries))
+    for _, entry := range entries {
+        if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+            continue
+        }
+        identity, err := url.PathUnescape(entry.Name())
+        if err != nil {
+            return nil, err
+        }
+        keys = append(keys, identity)
+    }
+    return keys, nil
+}
+
+// EntityCounts is how many of each entity a store holds.
+type EntityCounts struct {
+    Users      int
+    Subreddits int
+    Posts      int
+    Comments   int
+    Messages   int
+}
+
+// CountEntities counts what store holds, as saved: an entity whose save
+// is under way may or may not be counted. Direct messages are kept in
+// their recipients' inboxes, so counting them reads every user.
+func CountEntities(store GrainStore) (EntityCounts, error) {
+    counts := EntityCounts{}
+    for kind, count := range map[string]*int{
+        SubredditKind:    &counts.Subreddits,
+        postStoreKind:    &counts.Posts,
+        commentStoreKind: &counts.Comments,
+    } {
+        keys, err := store.Keys(kind)
+        if err != nil {
+            return counts, err
+        }
+        *count = len(keys)
+    }
+
+    userIDs, err := store.Keys(UserKind)
+    if err != nil {
+        return counts, err
+    }
+    counts.Users = len(userIDs)
+    for _, userID := range userIDs {
+        user := &messages.UserState{}
+        if _, err := store.Load(UserKind, userID, user); err != nil {
+            return counts, err
+        }
+        counts.Messages += len(user.Inbox)
+    }
+    return counts, nil
+}
@@ -0,0 +1,209 @@
+// internal/actors/subreddit_grain.go
+package actors
+
+import (
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/cluster"
+    "redditclone/internal/messages"
+    "redditclone/pkg/clock"
+    "google.golang.org/protobuf/proto"
+)
+
+// SubredditKind is the cluster kind of subreddit grains. A grain's identity
+// is its subreddit's ID.
+const SubredditKind = "subreddit"
+
+// subredditID is the ID, and so the grain identity, of the subreddit with
+// the given name. Names are unique, so creating a subreddit needs no
+// registry: the grain for its name either exists or it doesn't.
+func subredditID(name string) string {
+    return "r/" + name
+}
+
+func newSubredditKind(store GrainStore, passivateAfter time.Duration, clock clock.Clock, options []actor.PropsOption) *cluster.Kind {
+    return cluster.NewKind(SubredditKind, actor.PropsFromProducer(func() actor.Actor {
+        return NewSubredditGrain(store, passivateAfter, clock)
+    }, append(options, recoverGrain(SubredditKind))...))
+}
+
+// Receive handles the requests the subreddit manager passes on, about the
+// subreddit's members, moderators, posts and settings. The grain loads its
+// subreddit when activated and saves it before answering any request that
+// changed it, so it can be stopped at any time: after PassivateAfter
+// without a message, when the cluster moves it to another member, or with
+// the member it was on. One activated for an ID that was never created
+// stops as soon as it has answered.
+func (state *SubredditGrain) Receive(context actor.Context) {
+    if state.Passivation.receive(context) {
+        return
+    }
+
+    switch msg := context.Message().(type) {
+    case *cluster.ClusterInit:
+        state.Identity = msg.Identity.Identity
+        subreddit := &messages.SubReddit{}
+        found, err := state.claim(subreddit)
+        if err != nil {
+            state.LoadErr = err
+        } else if found {
+            state.Subreddit = subreddit
+            if state.Subreddit.Members == nil {
+                state.Subreddit.Members = make(map[string]bool)
+            }
+        }
+
+    case *messages.CreateSubRedditMsg:
+        if !state.loaded(context) {
+            return
+        }
+        if state.Subreddit != nil {
+            context.Respond(alreadyExists("name", "subreddit", msg.Name))
+            return
+        }
+        state.Subreddit = &messages.SubReddit{
+            Id:          state.Identity,
+            Name:        msg.Name,
+            Description: msg.Description,
+            Members:     make(map[string]bool),
+            Moderators:  map[string]bool{msg.UserId: true},
+            Settings:    &messages.SubredditSettings{},
+        }
+        state.commit(context, &messages.OperationResponse{
+            Success: true,
+            Id:      state.Identity,
+            Result: &messages.OperationResponse_Subreddit{
+                Subreddit: proto.Clone(state.Subreddit).(*messages.SubReddit),
+            },
+        })
+
+    case *messages.JoinSubRedditMsg:
+        if !state.exists(context) {
+            return
+        }
+        state.Subreddit.Members[msg.UserId] = true
+        state.commit(context, state.summary())
+
+    case *messages.LeaveSubRedditMsg:
+        if !state.exists(context) {
+            return
+        }
+        delete(state.Subreddit.Members, msg.UserId)
+        state.commit(context, state.summary())
+
+    case *messages.UpdateSubredditSettingsMsg:
+        if !state.exists(context) {
+            return
+        }
+        if !state.Subreddit.Moderators[msg.UserId] {
+            context.Respond(permissionDenied(msg.UserId, "change the settings of", "subreddit", state.Identity))
+            return
+        }
+        state.Subreddit.Settings = msg.Settings
+        if state.Subreddit.Settings == nil {
+            state.Subreddit.Settings = &messages.SubredditSettings{}
+        }
+        state.commit(context, &messages.OperationResponse{
+            Success: true,
+            Id:      state.Identity,
+            Result: &messages.OperationResponse_Subreddit{
+                Subreddit: proto.Clone(state.Subreddit).(*messages.SubReddit),
+            },
+        })
+
+    case *messages.IndexPostMsg:
+        if !state.exists(context) {
+            return
+        }
+        state.Subreddit.PostIds = append(state.Subreddit.PostIds, msg.PostId)
+        state.commit(context, &messages.OperationResponse{Success: true})
+
+    // The post's shard may give its ID out again once restarted, so only
+    // one listing of it goes
+    case *messages.UnindexPostMsg:
+        if !state.exists(context) {
+            return
+        }
+        for i, postID := range state.Subreddit.PostIds {
+            if postID == msg.PostId {
+                state.Subreddit.PostIds = append(state.Subreddit.PostIds[:i], state.Subreddit.PostIds[i+1:]...)
+                break
+            }
+        }
+        state.commit(context, &messages.OperationResponse{Success: true})
+
+    case *messages.GetSubRedditMsg:
+        if !state.exists(context) {
+            return
+        }
+        context.Respond(&messages.OperationResponse{
+            Success: true,
+            Id:      state.Identity,
+            Result: &messages.OperationResponse_Subreddit{
+                Subreddit: proto.Clone(state.Subreddit).(*messages.SubReddit),
+            },
+        })
+    }
+}
+
+// claim fences off any earlier activation of the subreddit, which may still
+// be running on a member that hasn't seen the cluster change, and then
+// loads what was saved.
+func (state *SubredditGrain) claim(saved *messages.SubReddit) (bool, error) {
+    lease, err := state.Store.Claim(SubredditKind, state.Identity)
+    if err != nil {
+        return false, err
+    }
+    state.Lease = lease
+    return state.Store.Load(SubredditKind, state.Identity, saved)
+}
+
+// loaded answers with an error if the grain's state couldn't be loaded,
+// and stops it so the next request activates it afresh.
+func (state *SubredditGrain) loaded(context actor.Context) bool {
+    if state.LoadErr == nil {
+        return true
+    }
+    context.Respond(internalError("subreddit %s could not be loaded: %v", state.Identity, state.LoadErr))
+    context.Stop(context.Self())
+    return false
+}
+
+// exists answers not found for a subreddit that was never created.
+func (state *SubredditGrain) exists(context actor.Context) bool {
+    if !state.loaded(context) {
+        return false
+    }
+    if state.Subreddit == nil {
+        context.Respond(notFound("subreddit", "subreddit", state.Identity))
+        context.Stop(context.Self())
+        return false
+    }
+    return true
+}
+
+// commit saves the subreddit before answering with response, as a user
+// grain does.
+func (state *SubredditGrain) commit(context actor.Context, response *messages.OperationResponse) {
+    if err := state.Store.Save(state.Lease, SubredditKind, state.Identity, state.Subreddit); err != nil {
+        context.Respond(internalError("subreddit %s could not be saved: %v", state.Identity, err))
+        context.Stop(context.Self())
+        return
+    }
+    context.Respond(response)
+}
+
+// summary answers a membership change with the subreddit's ID and name
+// only; member lists can be large.
+func (state *SubredditGrain) summary() *messages.OperationResponse {
+    return &messages.OperationResponse{
+        Success: true,
+        Id:      state.Identity,
+        Result: &messages.OperationResponse_Subreddit{
+            Subreddit: &messages.SubReddit{
+                Id:   state.Subreddit.Id,
+                Name: state.Subreddit.Name,
+            },
+        },
+    }
+}
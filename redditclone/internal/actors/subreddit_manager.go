@@ -2,49 +2,85 @@
 package actors
 
 import (
-    "fmt"
     "github.com/asynkron/protoactor-go/actor"
     "redditclone/internal/messages"
 )
 
+// Receive passes each request on to the grain of the subreddit it is about.
+// A join or leave checks with the user's grain first and is passed on to it
+// as well once it has succeeded, since users keep their subscriptions.
 func (state *SubRedditManagerActor) Receive(context actor.Context) {
     switch msg := context.Message().(type) {
     case *messages.CreateSubRedditMsg:
-        for _, subreddit := range state.Subreddits {
-            if subreddit.Name == msg.Name {
-                context.Respond(&messages.OperationResponse{
-                    Success: false,
-                    Error: "subreddit already exists",
-                })
-                return
-            }
+        if msg.Name == "" {
+            context.Respond(invalidArgument("name", "must not be empty"))
+            return
         }
 
-        subredditID := fmt.Sprintf("subreddit_%d", len(state.Subreddits)+1)
-        newSubreddit := &messages.SubReddit{
-            Name:       msg.Name,
-            Members:    make(map[string]bool),
-            Moderators: map[string]bool{msg.UserId: true},
+        if !state.Idempotency.begin(context, msg.IdempotencyKey) {
+            return
         }
-        state.Subreddits[subredditID] = newSubreddit
-
-        context.Respond(&messages.OperationResponse{
-            Success: true,
-            Id:      subredditID,
-            Result: &messages.OperationResponse_Subreddit{
-                Subreddit: newSubreddit,
-            },
+
+        future := requestGrain(context, state.Cluster, SubredditKind, subredditID(msg.Name), msg)
+        context.ReenterAfter(future, func(res interface{}, err error) {
+            state.Idempotency.finish(context, msg.IdempotencyKey, asResponse(res, err))
         })
 
     case *messages.JoinSubRedditMsg:
-        if subreddit, exists := state.Subreddits[msg.Subreddit]; exists {
-            subreddit.Members[msg.UserId] = true
-            context.Respond(&messages.OperationResponse{Success: true})
-        } else {
-            context.Respond(&messages.OperationResponse{
-                Success: false,
-                Error: "subreddit not found",
-            })
-        }
+        state.subscribe(context, msg.UserId, msg.Subreddit, msg, true)
+
+    case *messages.LeaveSubRedditMsg:
+        state.subscribe(context, msg.UserId, msg.Subreddit, msg, false)
+
+    case *messages.GetSubRedditMsg:
+        forwardToGrain(context, state.Cluster, SubredditKind, msg.Subreddit, msg)
+
+    case *messages.UpdateSubredditSettingsMsg:
+        forwardToGrain(context, state.Cluster, SubredditKind, msg.Subreddit, msg)
+
+    case *messages.IndexPostMsg:
+        forwardToGrain(context, state.Cluster, SubredditKind, msg.Subreddit, msg)
+
+    case *messages.UnindexPostMsg:
+        forwardToGrain(context, state.Cluster, SubredditKind, msg.Subreddit, msg)
+
+    case *messages.GetSubscriptionsMsg:
+        forwardToGrain(context, state.Cluster, UserKind, msg.UserId, msg)
     }
-}
\ No newline at end of file
+}
+
+// subscribe makes a join or leave. It checks that the user exists before
+// the subreddit grain changes its members, so a subreddit never lists a
+// user who doesn't, and records the change with the user once it has.
+func (state *SubRedditManagerActor) subscribe(context actor.Context, userID, subredditID string, msg interface{}, subscribed bool) {
+    user := requestGrain(context, state.Cluster, UserKind, userID, &messages.GetUserMsg{UserId: userID})
+    context.ReenterAfter(user, func(res interface{}, err error) {
+        if response := asResponse(res, err); !response.Success {
+            context.Respond(response)
+            return
+        }
+
+        future := requestGrain(context, state.Cluster, SubredditKind, subredditID, msg)
+        context.ReenterAfter(future, func(res interface{}, err error) {
+            response := asResponse(res, err)
+            if !response.Success {
+                context.Respond(response)
+                return
+            }
+            subreddit := response.GetSubreddit()
+            future := requestGrain(context, state.Cluster, UserKind, userID, &messages.SubscriptionMsg{
+                UserId:     userID,
+                Subreddit:  subreddit.GetId(),
+                Name:       subreddit.GetName(),
+                Subscribed: subscribed,
+            })
+            context.ReenterAfter(future, func(res interface{}, err error) {
+                if update := asResponse(res, err); !update.Success {
+                    context.Respond(update)
+                    return
+                }
+                context.Respond(response)
+            })
+        })
+    })
+}
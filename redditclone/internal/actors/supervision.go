@@ -0,0 +1,118 @@
+// internal/actors/supervision.go
+package actors
+
+import (
+    "fmt"
+    "log/slog"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+)
+
+// SupervisionConfig is how the managers are supervised.
+//
+// A manager that panics is restarted with a fresh actor. That loses
+// nothing: the data lives in grains, which save it to the store before
+// answering, and a manager holds only its idempotency keys, so a repeat of
+// a create that was in flight is processed again. But a manager that keeps
+// failing would keep failing, so after MaxRestarts within RestartWindow the
+// failure is escalated: the manager is stopped and Escalate is called,
+// which an engine uses to exit so whatever runs it starts it afresh.
+type SupervisionConfig struct {
+    MaxRestarts   int
+    RestartWindow time.Duration
+    Escalate      func(manager *actor.PID, reason interface{})
+}
+
+func DefaultSupervisionConfig() SupervisionConfig {
+    return SupervisionConfig{
+        MaxRestarts:   10,
+        RestartWindow: time.Minute,
+    }
+}
+
+// managerStrategy supervises a manager spawned with it as its guardian,
+// which keeps the manager at the root under its well-known name.
+type managerStrategy struct {
+    config SupervisionConfig
+}
+
+func newManagerStrategy(config SupervisionConfig) actor.SupervisorStrategy {
+    return &managerStrategy{config: config}
+}
+
+// HandleFailure logs the failure and the message that caused it, answers
+// that message's sender with an internal error so it isn't left to time
+// out, and restarts the manager, or escalates if it has failed too often.
+func (s *managerStrategy) HandleFailure(system *actor.ActorSystem, supervisor actor.Supervisor, child *actor.PID, rs *actor.RestartStatistics, reason interface{}, message interface{}) {
+    logFailure(system.Logger(), child, reason, message)
+    answerFailure(system, child, reason, message)
+
+    rs.Fail()
+    if failures := rs.NumberOfFailures(s.config.RestartWindow); failures > s.config.MaxRestarts {
+        system.Logger().Error("Manager keeps failing, escalating",
+            slog.Any("manager", child),
+            slog.Int("failures", failures),
+            slog.Duration("within", s.config.RestartWindow))
+        rs.Reset()
+        publishDirective(system, child, reason, actor.StopDirective)
+        supervisor.StopChildren(child)
+        if s.config.Escalate != nil {
+            s.config.Escalate(child, reason)
+        }
+        return
+    }
+    publishDirective(system, child, reason, actor.RestartDirective)
+    supervisor.RestartChildren(child)
+}
+
+// publishDirective tells the event stream what became of a failed actor, as
+// protoactor's own strategies do, for metrics.
+func publishDirective(system *actor.ActorSystem, child *actor.PID, reason interface{}, directive actor.Directive) {
+    system.EventStream.Publish(&actor.SupervisorEvent{
+        Child:     child,
+        Reason:    reason,
+        Directive: directive,
+    })
+}
+
+// recoverGrain is receiver middleware for grain kinds. A grain is spawned
+// by the cluster rather than at the root, so it can't have a guardian;
+// instead this logs a failure and answers the message that caused it, then
+// lets the panic through. The cluster restarts the grain, which gets a
+// ClusterInit again and reloads what it last saved, dropping whatever the
+// failed message had half changed. One that keeps failing is stopped, and
+// activated afresh by the next request.
+func recoverGrain(kind string) actor.PropsOption {
+    return actor.WithReceiverMiddleware(func(next actor.ReceiverFunc) actor.ReceiverFunc {
+        return func(c actor.ReceiverContext, envelope *actor.MessageEnvelope) {
+            defer func() {
+                if reason := recover(); reason != nil {
+                    logFailure(c.Logger().With(slog.String("kind", kind)), c.Self(), reason, envelope)
+                    answerFailure(c.ActorSystem(), c.Self(), reason, envelope)
+                    panic(reason)
+                }
+            }()
+            next(c, envelope)
+        }
+    })
+}
+
+func logFailure(logger *slog.Logger, who *actor.PID, reason interface{}, message interface{}) {
+    _, message, _ = actor.UnwrapEnvelope(message)
+    logger.Error("Actor failed",
+        slog.Any("actor", who),
+        slog.Any("reason", reason),
+        slog.String("message_type", fmt.Sprintf("%T", message)),
+        slog.Any("message", message))
+}
+
+// answerFailure answers the sender of the message an actor failed on, if
+// it had one. A failure in a continuation, after ReenterAfter, has no
+// message to go by, so its sender times out.
+func answerFailure(system *actor.ActorSystem, who *actor.PID, reason interface{}, message interface{}) {
+    envelope, ok := message.(*actor.MessageEnvelope)
+    if !ok || envelope.Sender == nil {
+        return
+    }
+    system.Root.Send(envelope.Sender, internalError("%s failed: %v", who.Id, reason))
+}
@@ -2,92 +2,134 @@
 package actors
 
 import (
-    "github.com/asynkron/protoactor-go/actor"
+    "time"
+    "github.com/asynkron/protoactor-go/cluster"
     "redditclone/internal/messages"
+    "redditclone/pkg/clock"
 )
 
 // Actor type definitions
 type UserManagerActor struct {
-    Users map[string]*messages.User
+    Cluster *cluster.Cluster
+    Idempotency *idempotencyCache
+}
+
+type UserGrain struct {
+    Identity string
+    State *messages.UserState // nil until registered
+    Lease Lease
+    LoadErr error
+    Store GrainStore
+    Passivation *passivation
+    Clock clock.Clock
 }
 
 type SubRedditManagerActor struct {
-    Subreddits map[string]*messages.SubReddit
+    Cluster *cluster.Cluster
+    Idempotency *idempotencyCache
+}
+
+type SubredditGrain struct {
+    Identity string
+    Subreddit *messages.SubReddit // nil until created
+    Lease Lease
+    LoadErr error
+    Store GrainStore
+    Passivation *passivation
 }
 
 type PostManagerActor struct {
+    Identity string
+    Cluster *cluster.Cluster
     Posts map[string]*messages.Post
-    UserManager *actor.PID
+    Votes map[string]map[string]bool
+    Lease Lease
+    LoadErr error
+    Store GrainStore
+    Idempotency *idempotencyCache
+    Clock clock.Clock
+    Ring *shardRing
+    LastID int
 }
 
 type CommentManagerActor struct {
+    Identity string
+    Cluster *cluster.Cluster
     Comments map[string]*messages.Comment
-    UserManager *actor.PID
+    ByPost map[string][]string
+    Votes map[string]map[string]bool
+    Lease Lease
+    LoadErr error
+    Store GrainStore
+    Idempotency *idempotencyCache
+    Clock clock.Clock
+    Ring *shardRing
+    PostRing *shardRing
+    LastID int
 }
 
 type MessageManagerActor struct {
-    Messages map[string][]*messages.DirectMessage
-}
-
-type SimulatorActor struct {
-    UserIDs         []string
-    Subreddits      []string
-    PostIDs         []string
-    CommentIDs      []string
-    ActiveUsers     map[string]bool
-    UserManager     *actor.PID
-    SubredditManager *actor.PID
-    PostManager     *actor.PID
-    CommentManager  *actor.PID
-    MessageManager  *actor.PID
-    NumUsers        int
-    Stats           *messages.SimulationStats
+    Cluster *cluster.Cluster
+    Idempotency *idempotencyCache
 }
 
 // Actor constructors
-func NewUserManagerActor() *UserManagerActor {
+func NewUserManagerActor(c *cluster.Cluster, idempotencyWindow time.Duration, clock clock.Clock) *UserManagerActor {
     return &UserManagerActor{
-        Users: make(map[string]*messages.User),
+        Cluster: c,
+        Idempotency: newIdempotencyCache(idempotencyWindow, clock),
+    }
+}
+
+func NewUserGrain(store GrainStore, passivateAfter time.Duration, clock clock.Clock) *UserGrain {
+    return &UserGrain{
+        Store: store,
+        Passivation: newPassivation(passivateAfter, clock),
+        Clock: clock,
     }
 }
 
-func NewSubRedditManagerActor() *SubRedditManagerActor {
+func NewSubRedditManagerActor(c *cluster.Cluster, idempotencyWindow time.Duration, clock clock.Clock) *SubRedditManagerActor {
     return &SubRedditManagerActor{
-        Subreddits: make(map[string]*messages.SubReddit),
+        Cluster: c,
+        Idempotency: newIdempotencyCache(idempotencyWindow, clock),
     }
 }
 
-func NewPostManagerActor() *PostManagerActor {
+func NewSubredditGrain(store GrainStore, passivateAfter time.Duration, clock clock.Clock) *SubredditGrain {
+    return &SubredditGrain{
+        Store: store,
+        Passivation: newPassivation(passivateAfter, clock),
+    }
+}
+
+func NewPostManagerActor(store GrainStore, idempotencyWindow time.Duration, clock clock.Clock, ring *shardRing) *PostManagerActor {
     return &PostManagerActor{
         Posts: make(map[string]*messages.Post),
+        Votes: make(map[string]map[string]bool),
+        Store: store,
+        Idempotency: newIdempotencyCache(idempotencyWindow, clock),
+        Clock: clock,
+        Ring: ring,
     }
 }
 
-func NewCommentManagerActor() *CommentManagerActor {
+func NewCommentManagerActor(store GrainStore, idempotencyWindow time.Duration, clock clock.Clock, ring, postRing *shardRing) *CommentManagerActor {
     return &CommentManagerActor{
         Comments: make(map[string]*messages.Comment),
+        ByPost: make(map[string][]string),
+        Votes: make(map[string]map[string]bool),
+        Store: store,
+        Idempotency: newIdempotencyCache(idempotencyWindow, clock),
+        Clock: clock,
+        Ring: ring,
+        PostRing: postRing,
     }
 }
 
-func NewMessageManagerActor() *MessageManagerActor {
+func NewMessageManagerActor(c *cluster.Cluster, idempotencyWindow time.Duration, clock clock.Clock) *MessageManagerActor {
     return &MessageManagerActor{
-        Messages: make(map[string][]*messages.DirectMessage),
+        Cluster: c,
+        Idempotency: newIdempotencyCache(idempotencyWindow, clock),
     }
 }
-
-func NewSimulatorActor(numUsers int, managerPIDs map[string]*actor.PID) *SimulatorActor {
-    return &SimulatorActor{
-        UserIDs:         make([]string, 0),
-        Subreddits:      make([]string, 0),
-        PostIDs:         make([]string, 0),
-        CommentIDs:      make([]string, 0),
-        ActiveUsers:     make(map[string]bool),
-        NumUsers:        numUsers,
-        UserManager:     managerPIDs["user-manager"],
-        SubredditManager: managerPIDs["subreddit-manager"],
-        PostManager:     managerPIDs["post-manager"],
-        CommentManager:  managerPIDs["comment-manager"],
-        MessageManager:  managerPIDs["message-manager"],
-        Stats:          &messages.SimulationStats{},
-    }
-}
\ No newline at end of file
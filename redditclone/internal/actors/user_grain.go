@@ -0,0 +1,256 @@
+// internal/actors/user_grain.go
+package actors
+
+import (
+    "fmt"
+    "sort"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/cluster"
+    "redditclone/internal/messages"
+    "redditclone/pkg/clock"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UserKind is the cluster kind of user grains. A grain's identity is its
+// user's ID.
+const UserKind = "user"
+
+// userID is the ID, and so the grain identity, of the user with the given
+// username, which is unique.
+func userID(username string) string {
+    return "u/" + username
+}
+
+func newUserKind(store GrainStore, passivateAfter time.Duration, clock clock.Clock, options []actor.PropsOption) *cluster.Kind {
+    return cluster.NewKind(UserKind, actor.PropsFromProducer(func() actor.Actor {
+        return NewUserGrain(store, passivateAfter, clock)
+    }, append(options, recoverGrain(UserKind))...))
+}
+
+// Receive handles the requests the user, subreddit and message managers
+// pass on. Like a subreddit grain, it loads and saves the user as a unit:
+// profile, karma ledger, inbox and subscriptions together.
+func (state *UserGrain) Receive(context actor.Context) {
+    if state.Passivation.receive(context) {
+        return
+    }
+
+    switch msg := context.Message().(type) {
+    case *cluster.ClusterInit:
+        state.Identity = msg.Identity.Identity
+        saved := &messages.UserState{}
+        found, err := state.claim(saved)
+        if err != nil {
+            state.LoadErr = err
+        } else if found {
+            state.State = saved
+            if state.State.KarmaLedger == nil {
+                state.State.KarmaLedger = make(map[string]*messages.KarmaVote)
+            }
+        }
+
+    case *messages.RegisterUserMsg:
+        if !state.loaded(context) {
+            return
+        }
+        if state.State != nil {
+            context.Respond(alreadyExists("username", "user", msg.Username))
+            return
+        }
+        state.State = &messages.UserState{
+            User: &messages.User{
+                Id:       state.Identity,
+                Username: msg.Username,
+                Joined:   timestamppb.New(state.Clock.Now()),
+            },
+            KarmaLedger: make(map[string]*messages.KarmaVote),
+        }
+        state.commit(context, state.profile())
+
+    case *messages.GetUserMsg:
+        if !state.exists(context) {
+            return
+        }
+        context.Respond(state.profile())
+
+    case *messages.UpdateKarmaMsg:
+        if !state.exists(context) {
+            return
+        }
+        key := msg.ItemId + "/" + msg.VoterId
+        previous := state.State.KarmaLedger[key]
+        if previous != nil && previous.Cast.AsTime().After(msg.Cast.AsTime()) {
+            context.Respond(&messages.OperationResponse{Success: true})
+            return
+        }
+        state.State.User.Karma += msg.Vote - previous.GetVote()
+        state.State.KarmaLedger[key] = &messages.KarmaVote{
+            Vote: msg.Vote,
+            Cast: msg.Cast,
+        }
+        state.commit(context, &messages.OperationResponse{Success: true})
+
+    case *messages.SaveItemMsg:
+        if !state.exists(context) {
+            return
+        }
+        user := state.State.User
+        saved := user.Saved[:0]
+        for _, itemID := range user.Saved {
+            if itemID != msg.ItemId {
+                saved = append(saved, itemID)
+            }
+        }
+        if msg.Saved {
+            saved = append(saved, msg.ItemId)
+        }
+        user.Saved = saved
+        state.commit(context, state.profile())
+
+    case *messages.SetPreferenceMsg:
+        if !state.exists(context) {
+            return
+        }
+        user := state.State.User
+        if msg.Value == "" {
+            delete(user.Preferences, msg.Key)
+        } else {
+            if user.Preferences == nil {
+                user.Preferences = make(map[string]string)
+            }
+            user.Preferences[msg.Key] = msg.Value
+        }
+        state.commit(context, state.profile())
+
+    case *messages.SendDirectMessageMsg:
+        // Sent to the recipient, whose inbox numbers its messages
+        if !state.exists(context) {
+            return
+        }
+        state.State.LastMessage++
+        newMessage := &messages.DirectMessage{
+            Id:         fmt.Sprintf("%s/msg_%d", state.Identity, state.State.LastMessage),
+            FromUserId: msg.FromUserId,
+            ToUserId:   msg.ToUserId,
+            Content:    msg.Content,
+            Timestamp:  timestamppb.New(state.Clock.Now()),
+        }
+        state.State.Inbox = append(state.State.Inbox, newMessage)
+        state.commit(context, &messages.OperationResponse{
+            Success: true,
+            Id:      newMessage.Id,
+            Result: &messages.OperationResponse_Message{
+                Message: proto.Clone(newMessage).(*messages.DirectMessage),
+            },
+        })
+
+    case *messages.SubscriptionMsg:
+        if !state.exists(context) {
+            return
+        }
+        if msg.Subscribed {
+            if state.State.Subscriptions == nil {
+                state.State.Subscriptions = make(map[string]string)
+            }
+            state.State.Subscriptions[msg.Subreddit] = msg.Name
+        } else {
+            delete(state.State.Subscriptions, msg.Subreddit)
+        }
+        state.commit(context, &messages.OperationResponse{Success: true})
+
+    case *messages.GetSubscriptionsMsg:
+        if !state.exists(context) {
+            return
+        }
+        // Only the ID and name are kept; member lists can be large
+        subscriptions := make([]*messages.SubReddit, 0, len(state.State.Subscriptions))
+        for subredditID, name := range state.State.Subscriptions {
+            subscriptions = append(subscriptions, &messages.SubReddit{
+                Id:   subredditID,
+                Name: name,
+            })
+        }
+        sort.Slice(subscriptions, func(i, j int) bool {
+            return subscriptions[i].Id < subscriptions[j].Id
+        })
+        context.Respond(&messages.OperationResponse{
+            Success:    true,
+            Subreddits: subscriptions,
+        })
+
+    case *messages.GetDirectMessagesMsg:
+        if !state.exists(context) {
+            return
+        }
+        // Inboxes are kept in the order messages arrived
+        inbox := make([]*messages.DirectMessage, 0, len(state.State.Inbox))
+        for _, message := range state.State.Inbox {
+            inbox = append(inbox, proto.Clone(message).(*messages.DirectMessage))
+        }
+        context.Respond(&messages.OperationResponse{
+            Success:  true,
+            Messages: inbox,
+        })
+    }
+}
+
+// claim fences off any earlier activation of the user, which may still
+// be running on a member that hasn't seen the cluster change, and then
+// loads what was saved.
+func (state *UserGrain) claim(saved *messages.UserState) (bool, error) {
+    lease, err := state.Store.Claim(UserKind, state.Identity)
+    if err != nil {
+        return false, err
+    }
+    state.Lease = lease
+    return state.Store.Load(UserKind, state.Identity, saved)
+}
+
+// loaded answers with an error if the grain's state couldn't be loaded,
+// and stops it so the next request activates it afresh.
+func (state *UserGrain) loaded(context actor.Context) bool {
+    if state.LoadErr == nil {
+        return true
+    }
+    context.Respond(internalError("user %s could not be loaded: %v", state.Identity, state.LoadErr))
+    context.Stop(context.Self())
+    return false
+}
+
+// exists answers not found for a user that never registered.
+func (state *UserGrain) exists(context actor.Context) bool {
+    if !state.loaded(context) {
+        return false
+    }
+    if state.State == nil {
+        context.Respond(notFound("user_id", "user", state.Identity))
+        context.Stop(context.Self())
+        return false
+    }
+    return true
+}
+
+// commit saves the user before answering with response, so a change that
+// was answered survives the grain moving to another member. If the save
+// fails it answers with an error and stops, dropping the change: the next
+// request activates the grain afresh from what was saved.
+func (state *UserGrain) commit(context actor.Context, response *messages.OperationResponse) {
+    if err := state.Store.Save(state.Lease, UserKind, state.Identity, state.State); err != nil {
+        context.Respond(internalError("user %s could not be saved: %v", state.Identity, err))
+        context.Stop(context.Self())
+        return
+    }
+    context.Respond(response)
+}
+
+func (state *UserGrain) profile() *messages.OperationResponse {
+    return &messages.OperationResponse{
+        Success: true,
+        Id:      state.Identity,
+        Result: &messages.OperationResponse_User{
+            User: proto.Clone(state.State.User).(*messages.User),
+        },
+    }
+}
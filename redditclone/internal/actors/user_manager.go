@@ -2,48 +2,45 @@
 package actors
 
 import (
-    "fmt"
     "github.com/asynkron/protoactor-go/actor"
     "redditclone/internal/messages"
 )
 
+// Receive passes each request on to the grain of the user it is about.
 func (state *UserManagerActor) Receive(context actor.Context) {
     switch msg := context.Message().(type) {
-    case *actor.Started:
-        if state.Users == nil {
-            state.Users = make(map[string]*messages.User)
-        }
-
     case *messages.RegisterUserMsg:
-        // Check if username exists
-        for _, user := range state.Users {
-            if user.Username == msg.Username {
-                if context.Sender() != nil {
-                    context.Send(context.Sender(), &messages.OperationResponse{
-                        Success: false,
-                        Error: "username already exists",
-                    })
-                }
-                return
-            }
+        if msg.Username == "" {
+            context.Respond(invalidArgument("username", "must not be empty"))
+            return
         }
 
-        userID := fmt.Sprintf("user_%d", len(state.Users)+1)
-        newUser := &messages.User{
-            Id:       userID,
-            Username: msg.Username,
-            Karma:    0,
+        if !state.Idempotency.begin(context, msg.IdempotencyKey) {
+            return
         }
-        state.Users[userID] = newUser
-
-        if context.Sender() != nil {
-            context.Send(context.Sender(), &messages.OperationResponse{
-                Success: true,
-                Id:      userID,
-                Result: &messages.OperationResponse_User{
-                    User: newUser,
-                },
-            })
+
+        future := requestGrain(context, state.Cluster, UserKind, userID(msg.Username), msg)
+        context.ReenterAfter(future, func(res interface{}, err error) {
+            state.Idempotency.finish(context, msg.IdempotencyKey, asResponse(res, err))
+        })
+
+    case *messages.UpdateKarmaMsg:
+        forwardToGrain(context, state.Cluster, UserKind, msg.UserId, msg)
+
+    case *messages.PingMsg:
+        context.Respond(&messages.OperationResponse{Success: true})
+
+    case *messages.GetUserMsg:
+        forwardToGrain(context, state.Cluster, UserKind, msg.UserId, msg)
+
+    case *messages.SaveItemMsg:
+        forwardToGrain(context, state.Cluster, UserKind, msg.UserId, msg)
+
+    case *messages.SetPreferenceMsg:
+        if msg.Key == "" {
+            context.Respond(invalidArgument("key", "must not be empty"))
+            return
         }
+        forwardToGrain(context, state.Cluster, UserKind, msg.UserId, msg)
     }
-}
\ No newline at end of file
+}
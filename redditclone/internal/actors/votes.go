@@ -0,0 +1,28 @@
+// internal/actors/votes.go
+package actors
+
+// applyVote records userID's vote on an item, whose voters map holds true
+// for upvotes and false for downvotes. It returns how the item's upvote and
+// downvote counts change: a repeated vote changes nothing, a switched vote
+// moves one count to the other.
+func applyVote(voters map[string]bool, userID string, isUpvote bool) (upDelta, downDelta int32) {
+    previous, voted := voters[userID]
+    if voted && previous == isUpvote {
+        return 0, 0
+    }
+    voters[userID] = isUpvote
+
+    if isUpvote {
+        upDelta++
+    } else {
+        downDelta++
+    }
+    if voted {
+        if previous {
+            upDelta--
+        } else {
+            downDelta--
+        }
+    }
+    return upDelta, downDelta
+}
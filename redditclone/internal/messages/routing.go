@@ -0,0 +1,40 @@
+// internal/messages/routing.go
+package messages
+
+// Hash methods make the post and comment messages router.Hasher, so the
+// routers in front of the sharded managers can hash them to a shard.
+// Everything about a post goes to the post shard its ID hashes to, and
+// everything about its comments to the comment shard its ID hashes to; the
+// shards give out post and comment IDs that hash back to themselves.
+
+// Hash routes repeats of a create with an idempotency key to the same
+// shard, whose cache has the answer. The shard picks the post's ID.
+func (m *CreatePostMsg) Hash() string {
+    if m.IdempotencyKey != "" {
+        return m.IdempotencyKey
+    }
+    return m.AuthorId + "/" + m.Title
+}
+
+func (m *GetPostMsg) Hash() string {
+    return m.PostId
+}
+
+// Hash spreads feeds across the shards; the one that gets a feed gathers
+// it from the others.
+func (m *GetFeedMsg) Hash() string {
+    return m.UserId
+}
+
+// Hash is the item's ID, which the owning post or comment shard gave out.
+func (m *VoteMsg) Hash() string {
+    return m.ItemId
+}
+
+func (m *CreateCommentMsg) Hash() string {
+    return m.PostId
+}
+
+func (m *GetCommentsMsg) Hash() string {
+    return m.PostId
+}
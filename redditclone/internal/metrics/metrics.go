@@ -0,0 +1,181 @@
+// internal/metrics/metrics.go
+package metrics
+
+import (
+    "fmt"
+    "net/http"
+    "strings"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/cluster/identitylookup/disthash"
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/collectors"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+    "redditclone/internal/actors"
+    "redditclone/internal/messages"
+)
+
+const namespace = "redditclone"
+
+// Metrics is an engine's Prometheus metrics: the requests its managers,
+// shards and grains answer, their mailboxes, what its store holds, and the
+// actors that failed or were sent messages they never got. Serve Handler
+// at /metrics.
+type Metrics struct {
+    registry    *prometheus.Registry
+    requests    *prometheus.CounterVec
+    latency     *prometheus.HistogramVec
+    restarts    *prometheus.CounterVec
+    deadLetters *prometheus.CounterVec
+    entities    *prometheus.GaugeVec
+}
+
+var _ actors.RequestObserver = (*Metrics)(nil)
+
+func New() *Metrics {
+    m := &Metrics{
+        registry: prometheus.NewRegistry(),
+        requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+            Namespace: namespace,
+            Name:      "requests_total",
+            Help:      "Requests answered, by the manager, shard or grain kind that answered, message type and error code.",
+        }, []string{"actor", "message", "code"}),
+        latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+            Namespace: namespace,
+            Name:      "request_duration_seconds",
+            Help:      "Time from a request arriving in a manager, shard or grain to its answer.",
+            Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 18), // 100µs to 13s
+        }, []string{"actor", "message"}),
+        restarts: prometheus.NewCounterVec(prometheus.CounterOpts{
+            Namespace: namespace,
+            Name:      "actor_restarts_total",
+            Help:      "Actors restarted after failing, by manager name, or grain for grains.",
+        }, []string{"actor"}),
+        deadLetters: prometheus.NewCounterVec(prometheus.CounterOpts{
+            Namespace: namespace,
+            Name:      "dead_letters_total",
+            Help:      "Messages sent to actors that no longer exist, by message type.",
+        }, []string{"message"}),
+        entities: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+            Namespace: namespace,
+            Name:      "entities",
+            Help:      "Entities in the store, by kind.",
+        }, []string{"kind"}),
+    }
+    m.registry.MustRegister(m.requests, m.latency, m.restarts, m.deadLetters,
+        collectors.NewGoCollector(),
+        collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+    return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+    return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveRequest(actorName, message string, code messages.ErrorCode, took time.Duration) {
+    m.requests.WithLabelValues(actorName, message, code.String()).Inc()
+    m.latency.WithLabelValues(actorName, message).Observe(took.Seconds())
+    if kind, ok := created[creation{actorName, message}]; ok && code == messages.ErrorCode_OK {
+        m.entities.WithLabelValues(kind).Inc()
+    }
+}
+
+// Watch counts system's restarts and dead letters, which its event stream
+// reports.
+func (m *Metrics) Watch(system *actor.ActorSystem) {
+    system.EventStream.Subscribe(func(event interface{}) {
+        switch event := event.(type) {
+        case *actor.SupervisorEvent:
+            if event.Directive == actor.RestartDirective {
+                m.restarts.WithLabelValues(actorLabel(event.Child)).Inc()
+            }
+        case *actor.DeadLetterEvent:
+            m.deadLetters.WithLabelValues(fmt.Sprintf("%T", event.Message)).Inc()
+        }
+    })
+}
+
+// actorLabel keeps restarts' label values few: a manager by name, any
+// grain as grain, since the cluster spawns grains under its placement
+// actor, and anything else as other.
+func actorLabel(pid *actor.PID) string {
+    switch id := pid.GetId(); {
+    case id == actors.UserManagerName, id == actors.SubredditManagerName, id == actors.PostManagerName,
+        id == actors.CommentManagerName, id == actors.MessageManagerName:
+        return id
+    case strings.HasPrefix(id, disthash.PartitionActivatorActorName+"/"):
+        return "grain"
+    }
+    return "other"
+}
+
+// CollectMailboxes reports stats on every scrape.
+func (m *Metrics) CollectMailboxes(stats *actors.MailboxStats) {
+    m.registry.MustRegister(&mailboxCollector{stats: stats})
+}
+
+var (
+    queuedDesc = prometheus.NewDesc(namespace+"_mailbox_queued",
+        "Requests waiting in a manager's mailboxes, its shards' included, or in a kind of grain's.", []string{"manager"}, nil)
+    rejectedDesc = prometheus.NewDesc(namespace+"_mailbox_rejected_total",
+        "Requests a manager's full mailbox turned away.", []string{"manager"}, nil)
+    droppedDesc = prometheus.NewDesc(namespace+"_mailbox_dropped_total",
+        "Requests a manager's full mailbox dropped to make room for newer ones.", []string{"manager"}, nil)
+)
+
+type mailboxCollector struct {
+    stats *actors.MailboxStats
+}
+
+func (c *mailboxCollector) Describe(descs chan<- *prometheus.Desc) {
+    descs <- queuedDesc
+    descs <- rejectedDesc
+    descs <- droppedDesc
+}
+
+func (c *mailboxCollector) Collect(metrics chan<- prometheus.Metric) {
+    for _, mailbox := range c.stats.Snapshot() {
+        metrics <- prometheus.MustNewConstMetric(queuedDesc, prometheus.GaugeValue, float64(mailbox.Queued), mailbox.Manager)
+        metrics <- prometheus.MustNewConstMetric(rejectedDesc, prometheus.CounterValue, float64(mailbox.Rejected), mailbox.Manager)
+        metrics <- prometheus.MustNewConstMetric(droppedDesc, prometheus.CounterValue, float64(mailbox.Dropped), mailbox.Manager)
+    }
+}
+
+// CollectEntities reports how many of each entity store holds. It counts
+// them once, reading the whole store, and then keeps count of those the
+// engine's grains and shards create, so scrapes read nothing. Engines
+// sharing a store each add only what they create to what they counted.
+func (m *Metrics) CollectEntities(store actors.GrainStore) error {
+    counts, err := actors.CountEntities(store)
+    if err != nil {
+        return err
+    }
+    for kind, count := range map[string]int{
+        "users":      counts.Users,
+        "subreddits": counts.Subreddits,
+        "posts":      counts.Posts,
+        "comments":   counts.Comments,
+        "messages":   counts.Messages,
+    } {
+        m.entities.WithLabelValues(kind).Add(float64(count))
+    }
+    m.registry.MustRegister(m.entities)
+    return nil
+}
+
+// creation is a request that, answered OK by the grain or shard it is for,
+// creates an entity.
+type creation struct {
+    actor   string
+    message string
+}
+
+// created is the kind of entity each creation creates. The managers answer
+// the same requests, but only once the grain or shard has.
+var created = map[creation]string{
+    {actors.UserKind, "RegisterUserMsg"}:          "users",
+    {actors.SubredditKind, "CreateSubRedditMsg"}:  "subreddits",
+    {actors.PostShardKind, "CreatePostMsg"}:       "posts",
+    {actors.CommentShardKind, "CreateCommentMsg"}: "comments",
+    {actors.UserKind, "SendDirectMessageMsg"}:     "messages",
+}
@@ -0,0 +1,184 @@
+// internal/registry/registry.go
+package registry
+
+import (
+    "fmt"
+    "log/slog"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+    "github.com/asynkron/protoactor-go/cluster"
+    "google.golang.org/protobuf/proto"
+)
+
+// Provider is a cluster provider for members on one machine that find each
+// other through a shared directory. Each member keeps a file there saying
+// where it listens and which kinds it hosts, rewriting it every TTL/3 as a
+// heartbeat, and reads everyone else's just as often. A member whose file
+// is older than TTL is taken to have failed and dropped from the topology,
+// so the cluster moves its grains to the members left; one that shuts down
+// removes its file, so it is dropped at once.
+type Provider struct {
+    dir  string
+    ttl  time.Duration
+    path string // of this member's file; empty for a client
+
+    mu      sync.Mutex
+    cluster *cluster.Cluster
+    member  *cluster.Member
+    stop    chan struct{}
+    stopped bool
+}
+
+var _ cluster.ClusterProvider = (*Provider)(nil)
+
+func New(dir string, ttl time.Duration) *Provider {
+    return &Provider{dir: dir, ttl: ttl}
+}
+
+// StartMember registers the member and publishes the first topology before
+// returning, so the member starts out knowing its peers.
+func (p *Provider) StartMember(c *cluster.Cluster) error {
+    host, port, err := c.ActorSystem.GetHostPort()
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(p.dir, 0o755); err != nil {
+        return fmt.Errorf("registry: %w", err)
+    }
+    p.cluster = c
+    p.member = &cluster.Member{
+        Id:    c.ActorSystem.ID,
+        Host:  host,
+        Port:  int32(port),
+        Kinds: c.GetClusterKinds(),
+    }
+    p.path = filepath.Join(p.dir, c.Config.Name+"."+p.member.Id)
+    if err := p.heartbeat(); err != nil {
+        return err
+    }
+    p.start()
+    return nil
+}
+
+// StartClient follows the topology without registering.
+func (p *Provider) StartClient(c *cluster.Cluster) error {
+    p.cluster = c
+    p.start()
+    return nil
+}
+
+func (p *Provider) Shutdown(graceful bool) error {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    if p.stopped {
+        return nil
+    }
+    p.stopped = true
+    if p.stop != nil {
+        close(p.stop)
+    }
+    if p.path != "" {
+        if err := os.Remove(p.path); err != nil && !os.IsNotExist(err) {
+            return err
+        }
+    }
+    return nil
+}
+
+func (p *Provider) start() {
+    p.stop = make(chan struct{})
+    p.refresh()
+    go func() {
+        ticker := time.NewTicker(p.ttl / 3)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-p.stop:
+                return
+            case <-ticker.C:
+                p.mu.Lock()
+                if !p.stopped {
+                    if p.path != "" {
+                        if err := p.heartbeat(); err != nil {
+                            p.cluster.Logger().Error("Failed to renew registration", slog.Any("error", err))
+                        }
+                    }
+                    p.refresh()
+                }
+                p.mu.Unlock()
+            }
+        }
+    }()
+}
+
+// heartbeat rewrites this member's file, which renews its modification
+// time. It writes a temporary file and renames it into place, so a reader
+// never sees half a member.
+func (p *Provider) heartbeat() error {
+    data, err := proto.Marshal(p.member)
+    if err != nil {
+        return err
+    }
+    tmp, err := os.CreateTemp(p.dir, ".member-*")
+    if err != nil {
+        return err
+    }
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        os.Remove(tmp.Name())
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        os.Remove(tmp.Name())
+        return err
+    }
+    return os.Rename(tmp.Name(), p.path)
+}
+
+// refresh publishes the members whose files are fresh.
+func (p *Provider) refresh() {
+    members, err := p.members()
+    if err != nil {
+        p.cluster.Logger().Error("Failed to read the registry", slog.Any("error", err))
+        return
+    }
+    p.cluster.MemberList.UpdateClusterTopology(members)
+}
+
+func (p *Provider) members() ([]*cluster.Member, error) {
+    entries, err := os.ReadDir(p.dir)
+    if err != nil {
+        return nil, err
+    }
+    prefix := p.cluster.Config.Name + "."
+    members := make([]*cluster.Member, 0, len(entries))
+    for _, entry := range entries {
+        if !strings.HasPrefix(entry.Name(), prefix) {
+            continue
+        }
+        info, err := entry.Info()
+        if err != nil {
+            continue // removed since the directory was read
+        }
+        if time.Since(info.ModTime()) > p.ttl {
+            continue
+        }
+        data, err := os.ReadFile(filepath.Join(p.dir, entry.Name()))
+        if err != nil {
+            continue
+        }
+        member := &cluster.Member{}
+        if err := proto.Unmarshal(data, member); err != nil {
+            return nil, fmt.Errorf("registry entry %s: %w", entry.Name(), err)
+        }
+        members = append(members, member)
+    }
+    sort.Slice(members, func(i, j int) bool {
+        return members[i].Id < members[j].Id
+    })
+    return members, nil
+}
@@ -0,0 +1,361 @@
+// internal/simulator/actions.go
+package simulator
+
+import (
+    "math"
+    "sort"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "redditclone/internal/messages"
+    "redditclone/pkg/client"
+)
+
+// act is one closed-loop turn: the user may go offline or come back, and if
+// connected and not still waiting on its last request, acts as often as its
+// persona and the time of day say.
+func (u *userActor) act(context actor.Context) {
+    now := u.Config.Clock.Now()
+    persona := dealPersona(u.Behavior.Personas, u.draw)
+    wasConnected := u.Connected
+    u.updateConnectivity(now, persona)
+    if u.Connected && !wasConnected {
+        u.catchUp(context)
+    } else if !u.Connected && wasConnected {
+        u.offlineAt = now
+    }
+    if !u.Connected || u.inFlight > 0 || u.backingOff() {
+        return
+    }
+    if chance := u.activity(now, persona); chance < 1 && u.rng.Float64() >= chance {
+        return
+    }
+    u.perform(context, 1)
+}
+
+// updateConnectivity takes the user offline or brings it back. With
+// sessions, it switches when the current session or gap is over; gaps
+// stretch at quiet times of day.
+func (u *userActor) updateConnectivity(now time.Time, persona *Persona) {
+    session, gap := u.Behavior.Connectivity.Session, u.Behavior.Connectivity.Gap
+    if persona != nil && persona.Session.set() {
+        session, gap = persona.Session, persona.Gap
+    }
+    if !session.set() {
+        connectivity := u.Behavior.Connectivity
+        if u.Connected && u.rng.Float64() < connectivity.Disconnect {
+            u.Connected = false
+        } else if !u.Connected && u.rng.Float64() < connectivity.Reconnect {
+            u.Connected = true
+        }
+        return
+    }
+
+    clock := u.Config.Scenario.Clock
+    switch {
+    case u.switchAt.IsZero():
+        // Users start partway through a session
+        u.switchAt = now.Add(time.Duration(u.rng.Float64() * float64(clock.real(session.draw(u.rng)))))
+    case now.Before(u.switchAt):
+    case u.Connected:
+        u.Connected = false
+        level := math.Max(u.level(now, persona), 0.05)
+        u.switchAt = now.Add(time.Duration(float64(clock.real(gap.draw(u.rng))) / level))
+    default:
+        u.Connected = true
+        u.switchAt = now.Add(clock.real(session.draw(u.rng)))
+    }
+}
+
+// level is how busy the user's diurnal curve is at this virtual time of
+// day, from 0 to 1.
+func (u *userActor) level(now time.Time, persona *Persona) float64 {
+    curve := u.Behavior.Diurnal
+    if persona != nil && len(persona.Diurnal) > 0 {
+        curve = persona.Diurnal
+    }
+    return diurnal(curve, u.Config.Scenario.Clock.TimeOfDay(now.Sub(u.started)))
+}
+
+// activity is the chance the user acts on a turn while online.
+func (u *userActor) activity(now time.Time, persona *Persona) float64 {
+    chance := 1.0
+    if persona != nil && persona.Activity > 0 {
+        chance = persona.Activity
+    }
+    return chance * u.level(now, persona)
+}
+
+// catchUp fetches what a user coming back online missed: its messages,
+// which it answers before starting new conversations, and its feed, whose
+// new posts it sees to before others.
+func (u *userActor) catchUp(context actor.Context) {
+    since := u.offlineAt
+    u.request(context, &messages.GetDirectMessagesMsg{
+        UserId: u.UserID,
+    }, func(response *messages.OperationResponse, err error) {
+        if err != nil {
+            return
+        }
+        u.unanswered = u.unanswered[:0]
+        for _, m := range response.Messages {
+            if m.FromUserId != u.UserID && m.Timestamp.AsTime().After(since) {
+                u.unanswered = append(u.unanswered, m.FromUserId)
+            }
+        }
+    })
+    u.request(context, &messages.GetFeedMsg{
+        UserId: u.UserID,
+    }, func(response *messages.OperationResponse, err error) {
+        if err != nil {
+            return
+        }
+        u.missed = u.missed[:0]
+        for _, p := range response.Posts {
+            if p.Timestamp.AsTime().After(since) {
+                u.missed = append(u.missed, p.Id)
+            }
+        }
+    })
+}
+
+// perform does an action picked by the persona's weights, or the
+// behaviour's without personas, picking again up to tries times if it finds
+// nothing to do, like commenting before there are posts. It reports whether
+// a request went out.
+func (u *userActor) perform(context actor.Context, tries int) bool {
+    weights := u.Behavior.Actions.weights()
+    if persona := dealPersona(u.Behavior.Personas, u.draw); persona != nil {
+        weights = persona.Actions.weights()
+    }
+    for ; tries > 0; tries-- {
+        inFlight := u.inFlight
+        switch weightedIndex(u.rng, weights) {
+        case 0:
+            u.simulateJoinSubreddit(context)
+        case 1:
+            u.simulateCreatePost(context)
+        case 2:
+            u.simulateRepost(context)
+        case 3:
+            u.simulateCreateComment(context)
+        case 4:
+            u.simulateVote(context)
+        case 5:
+            u.simulateDirectMessage(context)
+        case 6:
+            u.simulateRead(context)
+        }
+        if u.inFlight > inFlight {
+            return true
+        }
+    }
+    return false
+}
+
+// pickPost picks a recent post, the phase's hot post as often as the
+// behaviour's focus asks, and otherwise one the user missed while offline
+// if there are any left. Call only with posts in the catalog.
+func (u *userActor) pickPost() string {
+    if u.Catalog.Hot != "" && u.rng.Float64() < u.Behavior.Focus {
+        return u.Catalog.Hot
+    }
+    if n := len(u.missed); n > 0 {
+        postID := u.missed[n-1]
+        u.missed = u.missed[:n-1]
+        return postID
+    }
+    return u.Catalog.Posts[u.rng.Intn(len(u.Catalog.Posts))].ID
+}
+
+func (u *userActor) join(context actor.Context, subreddit int) {
+    if u.Joined[subreddit] {
+        return
+    }
+    u.request(context, &messages.JoinSubRedditMsg{
+        Subreddit: u.Subreddits[subreddit],
+        UserId:    u.UserID,
+    }, func(_ *messages.OperationResponse, err error) {
+        if err == nil && !u.Joined[subreddit] {
+            u.Joined[subreddit] = true
+            u.report(context, &activity{Kind: joinedSubreddit, Subreddit: subreddit})
+        }
+    })
+}
+
+// simulateJoinSubreddit joins a subreddit picked in proportion to its size,
+// so popular subreddits keep growing faster. The default behaviour makes
+// joins rare so the starting distribution holds for a while.
+func (u *userActor) simulateJoinSubreddit(context actor.Context) {
+    if i := weightedIndex(u.rng, u.Catalog.Members); i >= 0 {
+        u.join(context, i)
+    }
+}
+
+// simulateCreatePost posts to one of the user's subreddits, so each
+// subreddit's post rate follows its membership. As often as the behaviour
+// asks for duplicates, the post copies a recent one word for word, unlike
+// a marked repost, and counts as a repost.
+func (u *userActor) simulateCreatePost(context actor.Context) {
+    if len(u.Joined) == 0 {
+        return
+    }
+
+    // Sorted, so the seed alone decides which one
+    joined := make([]int, 0, len(u.Joined))
+    for subreddit := range u.Joined {
+        joined = append(joined, subreddit)
+    }
+    sort.Ints(joined)
+    subreddit := joined[u.rng.Intn(len(joined))]
+
+    if len(u.Catalog.Posts) > 0 && u.rng.Float64() < u.Behavior.Content.Duplicates {
+        original := u.Catalog.Posts[u.rng.Intn(len(u.Catalog.Posts))]
+        u.createPost(context, createdRepost, catalogPost{
+            Subreddit: subreddit,
+            Title:     original.Title,
+            Content:   original.Content,
+        })
+        return
+    }
+
+    u.createPost(context, createdPost, catalogPost{
+        Subreddit: subreddit,
+        Title:     u.compose(titleText),
+        Content:   u.compose(postText),
+    })
+}
+
+// simulateRepost copies a recent post into a subreddit picked in proportion
+// to its size; big subreddits attract most reposts.
+func (u *userActor) simulateRepost(context actor.Context) {
+    i := weightedIndex(u.rng, u.Catalog.Members)
+    if len(u.Catalog.Posts) == 0 || i < 0 {
+        return
+    }
+
+    original := u.Catalog.Posts[u.rng.Intn(len(u.Catalog.Posts))]
+    if original.Subreddit == i {
+        return
+    }
+
+    u.createPost(context, createdRepost, catalogPost{
+        Subreddit: i,
+        Title:     "[repost] " + original.Title,
+        Content:   original.Content,
+    })
+}
+
+func (u *userActor) createPost(context actor.Context, kind activityKind, p catalogPost) {
+    u.request(context, &messages.CreatePostMsg{
+        Title:          p.Title,
+        Content:        p.Content,
+        Subreddit:      u.Subreddits[p.Subreddit],
+        AuthorId:       u.UserID,
+        IdempotencyKey: client.NewIdempotencyKey(),
+    }, func(response *messages.OperationResponse, err error) {
+        if err == nil {
+            p.ID = response.Id
+            u.report(context, &activity{Kind: kind, Subreddit: p.Subreddit, Post: p})
+        }
+    })
+}
+
+// simulateCreateComment comments on a recent post, replying to one of its
+// recent comments as often as the behaviour's reply ratio.
+func (u *userActor) simulateCreateComment(context actor.Context) {
+    if len(u.Catalog.Posts) == 0 {
+        return
+    }
+
+    postID := u.pickPost()
+    parentID := ""
+    if u.rng.Float64() < u.Behavior.ReplyRatio {
+        for _, comment := range u.Catalog.Comments {
+            if comment.PostID == postID {
+                parentID = comment.ID
+            }
+        }
+    }
+
+    u.request(context, &messages.CreateCommentMsg{
+        Content:        u.compose(commentText),
+        PostId:         postID,
+        ParentId:       parentID,
+        AuthorId:       u.UserID,
+        IdempotencyKey: client.NewIdempotencyKey(),
+    }, func(response *messages.OperationResponse, err error) {
+        if err == nil {
+            u.report(context, &activity{
+                Kind:    createdComment,
+                Comment: catalogComment{ID: response.Id, PostID: postID},
+            })
+        }
+    })
+}
+
+func (u *userActor) simulateVote(context actor.Context) {
+    posts, comments := u.Catalog.Posts, u.Catalog.Comments
+    if len(posts) == 0 && len(comments) == 0 {
+        return
+    }
+
+    isPostVote := len(posts) > 0 && (len(comments) == 0 || u.rng.Float64() < 0.7)
+    var itemID string
+
+    if isPostVote {
+        itemID = u.pickPost()
+    } else {
+        itemID = comments[u.rng.Intn(len(comments))].ID
+    }
+
+    upvote := u.rng.Float64() < u.Behavior.UpvoteRatio
+    u.request(context, &messages.VoteMsg{
+        ItemId:   itemID,
+        UserId:   u.UserID,
+        IsUpvote: upvote,
+    }, func(_ *messages.OperationResponse, err error) {
+        if err == nil && u.Config.Verify {
+            u.report(context, &activity{Kind: voted, ItemID: itemID, Upvote: upvote})
+        }
+    })
+}
+
+func (u *userActor) simulateDirectMessage(context actor.Context) {
+    if len(u.UserIDs) < 2 {
+        return
+    }
+
+    var toUserID string
+    if n := len(u.unanswered); n > 0 {
+        toUserID = u.unanswered[n-1]
+        u.unanswered = u.unanswered[:n-1]
+    }
+    for toUserID == "" || toUserID == u.UserID {
+        toUserID = u.UserIDs[u.rng.Intn(len(u.UserIDs))]
+    }
+
+    u.request(context, &messages.SendDirectMessageMsg{
+        FromUserId:     u.UserID,
+        ToUserId:       toUserID,
+        Content:        u.compose(messageText),
+        IdempotencyKey: client.NewIdempotencyKey(),
+    }, func(response *messages.OperationResponse, err error) {
+        if err == nil {
+            u.report(context, &activity{Kind: sentMessage, ItemID: response.Id, ToUserID: toUserID})
+        }
+    })
+}
+
+// simulateRead reads the user's feed, or a recent post or its comments.
+func (u *userActor) simulateRead(context actor.Context) {
+    var msg interface{}
+    switch {
+    case len(u.Catalog.Posts) == 0 || u.rng.Float64() < 0.4:
+        msg = &messages.GetFeedMsg{UserId: u.UserID}
+    case u.rng.Float64() < 0.5:
+        msg = &messages.GetPostMsg{PostId: u.pickPost()}
+    default:
+        msg = &messages.GetCommentsMsg{PostId: u.pickPost()}
+    }
+    u.request(context, msg, func(*messages.OperationResponse, error) {})
+}
@@ -0,0 +1,169 @@
+// internal/simulator/content.go
+package simulator
+
+import (
+    "embed"
+    "fmt"
+    "math"
+    "math/rand"
+    "strings"
+    "sync"
+)
+
+//go:embed corpus/*.txt
+var corpus embed.FS
+
+type contentKind int
+
+const (
+    titleText contentKind = iota
+    postText
+    commentText
+    messageText
+)
+
+// defaultSizes are the lengths, in characters, of text a behaviour doesn't
+// size.
+var defaultSizes = [...]int{titleText: 80, postText: 300, commentText: 120, messageText: 80}
+
+// chain is a second-order Markov chain over the words of the bundled
+// corpus, one sentence per line. An empty word ends a sentence.
+type chain struct {
+    starts [][2]string
+    next   map[[2]string][]string
+}
+
+var (
+    wordsOnce sync.Once
+    words     *chain
+)
+
+// corpusChain builds the chain the first time it is needed; every user
+// shares it read-only.
+func corpusChain() *chain {
+    wordsOnce.Do(func() {
+        words = &chain{next: make(map[[2]string][]string)}
+        entries, _ := corpus.ReadDir("corpus")
+        for _, entry := range entries {
+            data, err := corpus.ReadFile("corpus/" + entry.Name())
+            if err != nil {
+                continue
+            }
+            for _, line := range strings.Split(string(data), "\n") {
+                sentence := strings.Fields(line)
+                if len(sentence) < 2 {
+                    continue
+                }
+                words.starts = append(words.starts, [2]string{sentence[0], sentence[1]})
+                for i := 0; i+2 <= len(sentence); i++ {
+                    following := ""
+                    if i+2 < len(sentence) {
+                        following = sentence[i+2]
+                    }
+                    key := [2]string{sentence[i], sentence[i+1]}
+                    words.next[key] = append(words.next[key], following)
+                }
+            }
+        }
+    })
+    return words
+}
+
+// sentence walks the chain from a random start to the end of a sentence,
+// stopping early past limit words.
+func (c *chain) sentence(rng *rand.Rand, limit int) []string {
+    start := c.starts[rng.Intn(len(c.starts))]
+    out := []string{start[0], start[1]}
+    for len(out) < limit {
+        candidates := c.next[[2]string{out[len(out)-2], out[len(out)-1]}]
+        if len(candidates) == 0 {
+            break
+        }
+        word := candidates[rng.Intn(len(candidates))]
+        if word == "" {
+            break
+        }
+        out = append(out, word)
+    }
+    return out
+}
+
+// compose generates text of the given kind at the behaviour's size, give
+// or take its spread. Titles are a single sentence; other text may carry a
+// link or mention another user as often as the behaviour says.
+func (u *userActor) compose(kind contentKind) string {
+    content := u.Behavior.Content
+    size := [...]int{content.Title, content.Post, content.Comment, content.Message}[kind]
+    if size == 0 {
+        size = defaultSizes[kind]
+    }
+    if content.Spread > 0 {
+        // A lognormal around size, as message lengths tend to be
+        size = int(float64(size) * math.Exp(content.Spread*u.rng.NormFloat64()-content.Spread*content.Spread/2))
+    }
+    size = max(size, 20)
+
+    c := corpusChain()
+    if kind == titleText {
+        return strings.TrimRight(u.sentences(c, size, 1), ".?!,")
+    }
+
+    mention := ""
+    if len(u.UserIDs) > 0 && u.rng.Float64() < content.Mentions {
+        mention = fmt.Sprintf("u/user_%d ", u.rng.Intn(len(u.UserIDs)))
+    }
+    link := ""
+    if u.rng.Float64() < content.Links {
+        word := strings.ToLower(c.starts[u.rng.Intn(len(c.starts))][0])
+        link = fmt.Sprintf(" https://example.com/%s/%d", word, u.rng.Intn(100000))
+    }
+    room := max(size-len(mention)-len(link), 20)
+    return mention + u.sentences(c, room, size) + link
+}
+
+// sentences strings whole sentences together up to size characters,
+// stopping at most sentences or when a few in a row don't fit. If none
+// fits at all, the last one tried is cut short.
+func (u *userActor) sentences(c *chain, size, most int) string {
+    var b strings.Builder
+    for n, misses := 0, 0; n < most && misses < 3; {
+        s := strings.Join(c.sentence(u.rng, size), " ")
+        if n > 0 {
+            s = " " + s
+        }
+        if b.Len()+len(s) > size {
+            if misses++; n == 0 && misses == 3 {
+                b.WriteString(truncate(s, size))
+            }
+            continue
+        }
+        b.WriteString(s)
+        n++
+    }
+    return b.String()
+}
+
+// dangling are words a cut sentence shouldn't end on.
+var dangling = map[string]bool{
+    "a": true, "an": true, "and": true, "the": true, "to": true, "of": true, "in": true, "on": true,
+    "for": true, "with": true, "but": true, "or": true, "so": true, "my": true, "I": true, "we": true,
+}
+
+// truncate cuts s to at most size characters at a word boundary, dropping
+// words the cut leaves dangling.
+func truncate(s string, size int) string {
+    if len(s) <= size {
+        return s
+    }
+    s = s[:size+1]
+    for {
+        cut := strings.LastIndexByte(s, ' ')
+        if cut <= 0 {
+            return s[:min(len(s), size)]
+        }
+        s = s[:cut]
+        if last := s[strings.LastIndexByte(s, ' ')+1:]; !dangling[last] {
+            return strings.TrimRight(s, ",")
+        }
+    }
+}
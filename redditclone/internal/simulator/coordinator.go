@@ -0,0 +1,415 @@
+// internal/simulator/coordinator.go
+package simulator
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "math/rand"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/scheduler"
+    "redditclone/internal/messages"
+    "redditclone/pkg/client"
+    "redditclone/pkg/clock"
+)
+
+// CoordinatorName is the name a coordinator is spawned under, so workers on
+// other nodes can find it.
+const CoordinatorName = "coordinator"
+
+// startDelay gives BeginWork time to reach every worker before the agreed
+// start.
+const startDelay = time.Second
+
+// CoordinatorConfig describes a distributed run. The scenario, rate, seed
+// and Zipf exponent are for the whole population; each worker gets its
+// share.
+type CoordinatorConfig struct {
+    Workers        int
+    Scenario       *Scenario
+    NumSubreddits  int // defaults to one per 20 users
+    ZipfExponent   float64
+    TickInterval   time.Duration
+    Rate           float64
+    Seed           int64
+    Duration       time.Duration // from the start; zero runs until stopped
+    ReportInterval time.Duration // defaults to 10s; negative for never
+    ReportJSON     string
+    ReportCSV      string
+    Label          string
+
+    // OnFinish, if set, is called once the run is over and reported.
+    OnFinish func()
+}
+
+// worker is the coordinator's view of one simulator process.
+type worker struct {
+    PID     *actor.PID // the worker's simulator, known once it is ready
+    Ready   bool
+    UserIDs []string
+    Stats   *messages.WorkerStats // latest running totals
+    Done    bool                  // stopped, or lost
+}
+
+// workerLost is a worker's Terminated, requeued behind its last messages.
+type workerLost struct {
+    PID *actor.PID
+}
+
+// CoordinatorActor runs a simulation across worker processes. Workers
+// register and get a partition of the users; once all have registered their
+// users it creates the subreddits, tells everyone to begin at the same
+// moment, and merges the stats they stream back into one report.
+type CoordinatorActor struct {
+    Config  CoordinatorConfig
+    Client  *client.Client
+    Workers []*worker
+
+    requests
+    phase         phase
+    stopping      bool
+    stopper       *actor.PID
+    rng           *rand.Rand
+    subreddits    []string // by rank, empty where creation failed
+    startedAt     time.Time
+    stopSummaries scheduler.CancelFunc
+    stopDeadline  scheduler.CancelFunc
+}
+
+func CoordinatorProps(config CoordinatorConfig, engineClient *client.Client) *actor.Props {
+    return actor.PropsFromProducer(func() actor.Actor {
+        return NewCoordinatorActor(config, engineClient)
+    })
+}
+
+func NewCoordinatorActor(config CoordinatorConfig, engineClient *client.Client) *CoordinatorActor {
+    if config.NumSubreddits < 1 {
+        config.NumSubreddits = max(config.Scenario.Population()/20, 1)
+    }
+    if config.TickInterval <= 0 {
+        config.TickInterval = 100 * time.Millisecond
+    }
+    if config.ReportInterval == 0 {
+        config.ReportInterval = 10 * time.Second
+    }
+    if config.Seed == 0 {
+        config.Seed = time.Now().UnixNano()
+    }
+
+    rng := rand.New(rand.NewSource(config.Seed))
+    return &CoordinatorActor{
+        Config:   config,
+        Client:   engineClient,
+        Workers:  make([]*worker, 0, config.Workers),
+        requests: requests{client: engineClient, clock: clock.Real{}, metrics: metrics{}, rng: rng},
+        rng:      rng,
+    }
+}
+
+func (state *CoordinatorActor) Receive(context actor.Context) {
+    switch msg := context.Message().(type) {
+    case *actor.Started:
+        log.Printf("Coordinator waiting for %d workers", state.Config.Workers)
+        state.phase = registering
+
+    case *messages.RegisterWorker:
+        state.register(context)
+
+    case *messages.WorkerReady:
+        if w := state.worker(msg.Worker); w != nil && !w.Ready {
+            w.PID = context.Sender()
+            w.Ready = true
+            w.UserIDs = msg.UserIds
+            context.Watch(w.PID)
+            log.Printf("Worker %d ready with %d users", msg.Worker, len(msg.UserIds))
+        }
+        state.advance(context)
+
+    case *messages.WorkerStats:
+        if w := state.worker(msg.Worker); w != nil && !w.Done {
+            w.Stats = msg
+            w.Done = msg.Final
+        }
+        state.advance(context)
+
+    case *summarize:
+        elapsed := time.Since(state.startedAt)
+        logOperations(fmt.Sprintf("Metrics from %d workers after %s", len(state.Workers), elapsed.Round(time.Second)),
+            operationReports(state.merged(), elapsed))
+
+    case *messages.PauseSimulation, *messages.ResumeSimulation:
+        for _, w := range state.Workers {
+            if w.Ready && !w.Done {
+                context.Send(w.PID, msg)
+            }
+        }
+
+    case *messages.StopSimulation:
+        if state.stopping {
+            return
+        }
+        state.stopping = true
+        state.stopper = context.Sender()
+        state.phase = stopped
+        state.stopTimers()
+        log.Printf("Stopping %d workers", len(state.Workers))
+        // Workers not yet ready stop when they lose the coordinator
+        for _, w := range state.Workers {
+            if w.Ready && !w.Done {
+                context.Request(w.PID, &messages.StopSimulation{})
+            }
+            w.Done = w.Done || !w.Ready
+        }
+        state.advance(context)
+
+    // Workers answer the stop with this too, after their final WorkerStats
+    case *messages.SimulationStats:
+
+    // Terminated is a system message and overtakes what the worker sent
+    // before it stopped, so it goes to the back of the queue first
+    case *actor.Terminated:
+        context.Send(context.Self(), &workerLost{PID: msg.Who})
+
+    case *workerLost:
+        for i, w := range state.Workers {
+            if w.Ready && w.PID.Equal(msg.PID) && !w.Done {
+                log.Printf("Lost worker %d", i)
+                w.Done = true
+            }
+        }
+        state.advance(context)
+
+    case *actor.Stopping:
+        state.stopTimers()
+
+    case *actor.Stopped, *actor.Restarting:
+
+    default:
+        log.Printf("Received unknown message: %v", msg)
+    }
+}
+
+// register takes on a worker and answers with its partition, or turns it
+// away once there are enough.
+func (state *CoordinatorActor) register(context actor.Context) {
+    if state.phase != registering || len(state.Workers) == state.Config.Workers {
+        log.Printf("Turning away worker %v, the run is full", context.Sender())
+        return
+    }
+
+    scenario, err := json.Marshal(state.Config.Scenario)
+    if err != nil {
+        log.Printf("Cannot send the scenario: %v", err)
+        return
+    }
+
+    index := len(state.Workers)
+    state.Workers = append(state.Workers, &worker{})
+    context.Respond(&messages.WorkerAssignment{
+        Worker:       int32(index),
+        Workers:      int32(state.Config.Workers),
+        Scenario:     scenario,
+        Seed:         state.Config.Seed,
+        Rate:         state.Config.Rate,
+        TickInterval: int64(state.Config.TickInterval),
+        ZipfExponent: state.Config.ZipfExponent,
+    })
+    log.Printf("Worker %d registered from %s", index, context.Sender().Address)
+}
+
+func (state *CoordinatorActor) worker(index int32) *worker {
+    if index < 0 || int(index) >= len(state.Workers) {
+        return nil
+    }
+    return state.Workers[index]
+}
+
+// advance moves to the next phase once the current one is done.
+func (state *CoordinatorActor) advance(context actor.Context) {
+    switch state.phase {
+    case registering:
+        if len(state.Workers) < state.Config.Workers {
+            return
+        }
+        for _, w := range state.Workers {
+            if !w.Ready && !w.Done {
+                return
+            }
+        }
+        state.createSubreddits(context)
+
+    case creatingSubreddits:
+        if state.inFlight == 0 {
+            state.begin(context)
+        }
+
+    case stopped:
+        if !state.stopping || state.inFlight > 0 {
+            return
+        }
+        for _, w := range state.Workers {
+            if !w.Done {
+                return
+            }
+        }
+        state.finish(context)
+    }
+}
+
+func (state *CoordinatorActor) userIDs() []string {
+    userIDs := make([]string, 0)
+    for _, w := range state.Workers {
+        userIDs = append(userIDs, w.UserIDs...)
+    }
+    return userIDs
+}
+
+// createSubreddits creates the run's subreddits on behalf of the workers,
+// each by a random user.
+func (state *CoordinatorActor) createSubreddits(context actor.Context) {
+    userIDs := state.userIDs()
+    if len(userIDs) == 0 {
+        log.Printf("No users registered, stopping simulation")
+        state.phase = stopped
+        return
+    }
+
+    log.Printf("Creating %d subreddits...", state.Config.NumSubreddits)
+    state.phase = creatingSubreddits
+    state.subreddits = make([]string, state.Config.NumSubreddits)
+    for i := range state.subreddits {
+        subredditName := fmt.Sprintf("subreddit_%d", i)
+        state.send(context, &messages.CreateSubRedditMsg{
+            Name:           subredditName,
+            UserId:         userIDs[state.rng.Intn(len(userIDs))],
+            IdempotencyKey: client.NewIdempotencyKey(),
+        }, func(response *messages.OperationResponse, err error) {
+            if err != nil {
+                log.Printf("Failed to create %s: %v", subredditName, err)
+            } else {
+                state.subreddits[i] = response.Id
+            }
+            state.advance(context)
+        })
+    }
+}
+
+// begin tells every worker to begin at the same moment, and arranges for
+// the run to end after its duration.
+func (state *CoordinatorActor) begin(context actor.Context) {
+    state.phase = running
+
+    subreddits := make([]string, 0, len(state.subreddits))
+    for _, subredditID := range state.subreddits {
+        if subredditID != "" {
+            subreddits = append(subreddits, subredditID)
+        }
+    }
+
+    startAt := time.Now().Add(startDelay)
+    begin := &messages.BeginWork{
+        SubredditIds: subreddits,
+        UserIds:      state.userIDs(),
+        StartAt:      startAt.UnixNano(),
+    }
+    for _, w := range state.Workers {
+        if !w.Done {
+            context.Send(w.PID, begin)
+        }
+    }
+    log.Printf("Workers begin at %s with %d users and %d subreddits",
+        startAt.Format("15:04:05.000"), len(begin.UserIds), len(subreddits))
+
+    state.startedAt = startAt
+    timers := scheduler.NewTimerScheduler(context.ActorSystem().Root)
+    if state.Config.ReportInterval > 0 {
+        state.stopSummaries = timers.SendRepeatedly(startDelay+state.Config.ReportInterval,
+            state.Config.ReportInterval, context.Self(), &summarize{})
+    }
+    if state.Config.Duration > 0 {
+        state.stopDeadline = timers.SendOnce(startDelay+state.Config.Duration, context.Self(), &messages.StopSimulation{})
+    }
+}
+
+func (state *CoordinatorActor) stopTimers() {
+    for _, cancel := range []*scheduler.CancelFunc{&state.stopSummaries, &state.stopDeadline} {
+        if *cancel != nil {
+            (*cancel)()
+            *cancel = nil
+        }
+    }
+}
+
+// merged adds up the workers' latest totals and the coordinator's own
+// requests.
+func (state *CoordinatorActor) merged() metrics {
+    m := metrics{}
+    m.merge(state.metrics)
+    for _, w := range state.Workers {
+        if w.Stats != nil {
+            m.merge(metricsFromProto(w.Stats.Operations))
+        }
+    }
+    return m
+}
+
+// finish writes the merged report, answers the stop request and stops.
+func (state *CoordinatorActor) finish(context actor.Context) {
+    if state.startedAt.IsZero() {
+        state.startedAt = time.Now()
+    }
+    elapsed := max(time.Since(state.startedAt), time.Millisecond)
+    total := &messages.SimulationStats{}
+    report := &Report{
+        Label:           state.Config.Label,
+        Scenario:        state.Config.Scenario.Name,
+        StartedAt:       state.startedAt,
+        DurationSeconds: elapsed.Seconds(),
+        Users:           len(state.userIDs()),
+        Operations:      operationReports(state.merged(), elapsed),
+        TargetRate:      state.Config.Rate,
+    }
+    for _, w := range state.Workers {
+        if w.Stats == nil {
+            continue
+        }
+        report.Scheduled += w.Stats.Scheduled
+        report.Behind = report.Behind || w.Stats.Behind
+        total.RegisteredUsers += w.Stats.Stats.GetRegisteredUsers()
+        total.TotalPosts += w.Stats.Stats.GetTotalPosts()
+        total.TotalComments += w.Stats.Stats.GetTotalComments()
+        total.TotalMessages += w.Stats.Stats.GetTotalMessages()
+    }
+    for _, subredditID := range state.subreddits {
+        if subredditID != "" {
+            total.ActiveSubreddits++
+        }
+    }
+    report.Subreddits = int(total.ActiveSubreddits)
+    report.Posts, report.Comments, report.Messages = total.TotalPosts, total.TotalComments, total.TotalMessages
+
+    if report.Behind {
+        log.Printf("The engine fell behind %.0f requests/s during the run", state.Config.Rate)
+    }
+    logOperations(fmt.Sprintf("Final metrics from %d workers after %s", len(state.Workers), elapsed.Round(time.Second)),
+        report.Operations)
+    if state.Config.ReportJSON != "" {
+        if err := report.WriteJSON(state.Config.ReportJSON); err != nil {
+            log.Printf("Failed to write %s: %v", state.Config.ReportJSON, err)
+        }
+    }
+    if state.Config.ReportCSV != "" {
+        if err := report.WriteCSV(state.Config.ReportCSV); err != nil {
+            log.Printf("Failed to write %s: %v", state.Config.ReportCSV, err)
+        }
+    }
+
+    if state.stopper != nil {
+        context.Send(state.stopper, total)
+    }
+    context.Stop(context.Self())
+    if state.Config.OnFinish != nil {
+        state.Config.OnFinish()
+    }
+}
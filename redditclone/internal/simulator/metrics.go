@@ -0,0 +1,233 @@
+// internal/simulator/metrics.go
+package simulator
+
+import (
+    "errors"
+    "math"
+    "sort"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "redditclone/internal/messages"
+)
+
+// Latencies are bucketed on a log scale, each bucket 2% wider than the one
+// before, so percentiles are within 2% and a histogram only holds the
+// buckets it has seen.
+const bucketGrowth = 1.02
+
+type histogram struct {
+    Buckets map[int]uint64
+    Count   uint64
+    Sum     time.Duration
+    Max     time.Duration
+}
+
+func newHistogram() *histogram {
+    return &histogram{Buckets: make(map[int]uint64)}
+}
+
+func (h *histogram) record(d time.Duration) {
+    bucket := 0
+    if d > 1 {
+        bucket = int(math.Log(float64(d)) / math.Log(bucketGrowth))
+    }
+    h.Buckets[bucket]++
+    h.Count++
+    h.Sum += d
+    h.Max = max(h.Max, d)
+}
+
+func (h *histogram) merge(other *histogram) {
+    for bucket, count := range other.Buckets {
+        h.Buckets[bucket] += count
+    }
+    h.Count += other.Count
+    h.Sum += other.Sum
+    h.Max = max(h.Max, other.Max)
+}
+
+// quantile returns the upper bound of the bucket holding the q-th latency.
+func (h *histogram) quantile(q float64) time.Duration {
+    if h.Count == 0 {
+        return 0
+    }
+
+    buckets := make([]int, 0, len(h.Buckets))
+    for bucket := range h.Buckets {
+        buckets = append(buckets, bucket)
+    }
+    sort.Ints(buckets)
+
+    rank := uint64(math.Ceil(q * float64(h.Count)))
+    var seen uint64
+    for _, bucket := range buckets {
+        seen += h.Buckets[bucket]
+        if seen >= rank {
+            return min(time.Duration(math.Pow(bucketGrowth, float64(bucket+1))), h.Max)
+        }
+    }
+    return h.Max
+}
+
+func (h *histogram) mean() time.Duration {
+    if h.Count == 0 {
+        return 0
+    }
+    return h.Sum / time.Duration(h.Count)
+}
+
+// opMetrics counts the outcomes of one kind of request. Timeouts and
+// overloads, requests a full manager turned away, are failures too. Lag is how late requests went out against their schedule;
+// latency includes it.
+type opMetrics struct {
+    Count      uint64
+    Successes  uint64
+    Failures   uint64
+    Timeouts   uint64
+    Overloaded uint64
+    Latency    *histogram
+    Lag        *histogram
+}
+
+func newOpMetrics() *opMetrics {
+    return &opMetrics{Latency: newHistogram(), Lag: newHistogram()}
+}
+
+// metrics holds opMetrics by operation name. Each actor fills its own; users
+// hand theirs to the simulator and start afresh.
+type metrics map[string]*opMetrics
+
+func (m metrics) record(op string, latency, lag time.Duration, err error) {
+    stats, exists := m[op]
+    if !exists {
+        stats = newOpMetrics()
+        m[op] = stats
+    }
+
+    stats.Count++
+    stats.Latency.record(latency)
+    stats.Lag.record(lag)
+    switch {
+    case err == nil:
+        stats.Successes++
+    case errors.Is(err, actor.ErrTimeout):
+        stats.Failures++
+        stats.Timeouts++
+    case overloaded(err):
+        stats.Failures++
+        stats.Overloaded++
+    default:
+        stats.Failures++
+    }
+}
+
+func (m metrics) merge(other metrics) {
+    for op, stats := range other {
+        mine, exists := m[op]
+        if !exists {
+            mine = newOpMetrics()
+            m[op] = mine
+        }
+        mine.Count += stats.Count
+        mine.Successes += stats.Successes
+        mine.Failures += stats.Failures
+        mine.Timeouts += stats.Timeouts
+        mine.Overloaded += stats.Overloaded
+        mine.Latency.merge(stats.Latency)
+        mine.Lag.merge(stats.Lag)
+    }
+}
+
+func (m metrics) total() uint64 {
+    var total uint64
+    for _, stats := range m {
+        total += stats.Count
+    }
+    return total
+}
+
+// toProto and metricsFromProto carry metrics between simulator processes.
+func (m metrics) toProto() []*messages.OperationStats {
+    ops := make([]*messages.OperationStats, 0, len(m))
+    for name, stats := range m {
+        ops = append(ops, &messages.OperationStats{
+            Name:       name,
+            Count:      stats.Count,
+            Successes:  stats.Successes,
+            Failures:   stats.Failures,
+            Timeouts:   stats.Timeouts,
+            Overloaded: stats.Overloaded,
+            Latency:    stats.Latency.toProto(),
+            Lag:        stats.Lag.toProto(),
+        })
+    }
+    return ops
+}
+
+func metricsFromProto(ops []*messages.OperationStats) metrics {
+    m := metrics{}
+    for _, op := range ops {
+        m[op.Name] = &opMetrics{
+            Count:      op.Count,
+            Successes:  op.Successes,
+            Failures:   op.Failures,
+            Timeouts:   op.Timeouts,
+            Overloaded: op.Overloaded,
+            Latency:    histogramFromProto(op.Latency),
+            Lag:        histogramFromProto(op.Lag),
+        }
+    }
+    return m
+}
+
+func (h *histogram) toProto() *messages.Histogram {
+    buckets := make(map[int32]uint64, len(h.Buckets))
+    for bucket, count := range h.Buckets {
+        buckets[int32(bucket)] = count
+    }
+    return &messages.Histogram{Buckets: buckets, Count: h.Count, Sum: int64(h.Sum), Max: int64(h.Max)}
+}
+
+func histogramFromProto(p *messages.Histogram) *histogram {
+    h := newHistogram()
+    for bucket, count := range p.GetBuckets() {
+        h.Buckets[int(bucket)] = count
+    }
+    h.Count = p.GetCount()
+    h.Sum = time.Duration(p.GetSum())
+    h.Max = time.Duration(p.GetMax())
+    return h
+}
+
+// opName names the operation a request message performs.
+func opName(msg interface{}) string {
+    switch msg.(type) {
+    case *messages.RegisterUserMsg:
+        return "register_user"
+    case *messages.CreateSubRedditMsg:
+        return "create_subreddit"
+    case *messages.JoinSubRedditMsg:
+        return "join_subreddit"
+    case *messages.LeaveSubRedditMsg:
+        return "leave_subreddit"
+    case *messages.CreatePostMsg:
+        return "create_post"
+    case *messages.CreateCommentMsg:
+        return "create_comment"
+    case *messages.VoteMsg:
+        return "vote"
+    case *messages.SendDirectMessageMsg:
+        return "send_message"
+    case *messages.GetFeedMsg:
+        return "get_feed"
+    case *messages.GetPostMsg:
+        return "get_post"
+    case *messages.GetCommentsMsg:
+        return "get_comments"
+    case *messages.GetDirectMessagesMsg:
+        return "get_messages"
+    case *messages.GetUserMsg:
+        return "get_user"
+    }
+    return "other"
+}
@@ -0,0 +1,222 @@
+// internal/simulator/persona.go
+package simulator
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "math/rand"
+    "time"
+)
+
+// Persona is a kind of user with its own mix of actions. Users are dealt
+// personas in proportion to their shares and keep them for the whole run;
+// a phase that lists other personas deals again by the same draw, so the
+// users' places in the population hold when only the mixes change.
+type Persona struct {
+    Name     string        `yaml:"name"`
+    Share    float64       `yaml:"share"`    // relative share of the population
+    Actions  ActionWeights `yaml:"actions"`  // zero takes the standard persona's
+    Activity float64       `yaml:"activity"` // chance of acting on a turn while online; zero takes the standard persona's, or 1
+    Session  Distribution  `yaml:"session"`  // overrides the behaviour's sessions
+    Gap      Distribution  `yaml:"gap"`
+    Diurnal  []float64     `yaml:"diurnal"`  // overrides the behaviour's curve
+}
+
+// StandardPersonas are the personas a scenario can name without spelling
+// out, each with an even share.
+func StandardPersonas() []Persona {
+    return []Persona{
+        {Name: "lurker", Share: 1, Activity: 0.3, Actions: ActionWeights{Read: 20, Vote: 1}},
+        {Name: "voter", Share: 1, Activity: 0.6, Actions: ActionWeights{Join: 1, Read: 5, Vote: 20}},
+        {Name: "commenter", Share: 1, Activity: 0.6, Actions: ActionWeights{Read: 5, Comment: 20, Vote: 5, Message: 2}},
+        {Name: "poster", Share: 1, Activity: 0.5, Actions: ActionWeights{Join: 1, Read: 3, Post: 10, Repost: 3, Comment: 3, Vote: 2}},
+        {Name: "power user", Share: 1, Activity: 1, Actions: ActionWeights{Join: 1, Read: 5, Post: 10, Repost: 5, Comment: 15, Vote: 15, Message: 5}},
+        {Name: "spammer", Share: 1, Activity: 1, Actions: ActionWeights{Post: 10, Repost: 10, Comment: 5, Message: 20}},
+    }
+}
+
+// fillStandard completes a persona that names a standard one and leaves
+// its actions or activity out.
+func (p *Persona) fillStandard() {
+    for _, standard := range StandardPersonas() {
+        if standard.Name != p.Name {
+            continue
+        }
+        if p.Actions == (ActionWeights{}) {
+            p.Actions = standard.Actions
+        }
+        if p.Activity == 0 {
+            p.Activity = standard.Activity
+        }
+    }
+}
+
+func (p *Persona) validate() []error {
+    var errs []error
+    if p.Name == "" {
+        errs = append(errs, errors.New("persona without a name"))
+    }
+    if p.Share < 0 {
+        errs = append(errs, fmt.Errorf("persona %s: negative share", p.Name))
+    }
+    if p.Activity < 0 || p.Activity > 1 {
+        errs = append(errs, fmt.Errorf("persona %s: activity %v is not between 0 and 1", p.Name, p.Activity))
+    }
+    for _, err := range p.Actions.validate() {
+        errs = append(errs, fmt.Errorf("persona %s: %w", p.Name, err))
+    }
+    if p.Session.set() != p.Gap.set() {
+        errs = append(errs, fmt.Errorf("persona %s: session and gap go together", p.Name))
+    }
+    for _, d := range []Distribution{p.Session, p.Gap} {
+        if err := d.validate(); err != nil {
+            errs = append(errs, fmt.Errorf("persona %s: %w", p.Name, err))
+        }
+    }
+    if err := validateDiurnal(p.Diurnal); err != nil {
+        errs = append(errs, fmt.Errorf("persona %s: %w", p.Name, err))
+    }
+    return errs
+}
+
+// dealPersona returns the persona a user with the given draw, between 0 and
+// 1, has among personas, or nil if there are none.
+func dealPersona(personas []Persona, draw float64) *Persona {
+    total := 0.0
+    for _, p := range personas {
+        total += p.Share
+    }
+    target := draw * total
+    for i := range personas {
+        if target < personas[i].Share {
+            return &personas[i]
+        }
+        target -= personas[i].Share
+    }
+    if len(personas) == 0 {
+        return nil
+    }
+    return &personas[len(personas)-1]
+}
+
+// Distribution is a random length of time, in virtual time. Kind is fixed
+// (always Mean), uniform (between Min and Max), exponential (around Mean)
+// or lognormal (around Mean, spread by Sigma). Min and Max also bound the
+// other kinds when set.
+type Distribution struct {
+    Kind  string        `yaml:"kind"`
+    Mean  time.Duration `yaml:"mean"`
+    Min   time.Duration `yaml:"min"`
+    Max   time.Duration `yaml:"max"`
+    Sigma float64       `yaml:"sigma"`
+}
+
+// set reports whether the distribution was given; the zero one wasn't.
+func (d Distribution) set() bool {
+    return d.Kind != ""
+}
+
+func (d Distribution) validate() error {
+    if !d.set() {
+        return nil
+    }
+    switch d.Kind {
+    case "fixed", "exponential", "lognormal":
+        if d.Mean <= 0 {
+            return fmt.Errorf("%s distribution needs a positive mean", d.Kind)
+        }
+    case "uniform":
+        if d.Max <= d.Min {
+            return errors.New("uniform distribution needs max above min")
+        }
+    default:
+        return fmt.Errorf("unknown distribution %q (have fixed, uniform, exponential, lognormal)", d.Kind)
+    }
+    if d.Min < 0 || d.Sigma < 0 || (d.Max > 0 && d.Max < d.Min) {
+        return fmt.Errorf("%s distribution has bad bounds", d.Kind)
+    }
+    return nil
+}
+
+func (d Distribution) draw(rng *rand.Rand) time.Duration {
+    var x float64
+    switch d.Kind {
+    case "fixed":
+        x = float64(d.Mean)
+    case "uniform":
+        x = float64(d.Min) + rng.Float64()*float64(d.Max-d.Min)
+    case "exponential":
+        x = rng.ExpFloat64() * float64(d.Mean)
+    case "lognormal":
+        // The mean of a lognormal is exp(mu + sigma²/2)
+        mu := math.Log(float64(d.Mean)) - d.Sigma*d.Sigma/2
+        x = math.Exp(mu + d.Sigma*rng.NormFloat64())
+    }
+    if d.Max > 0 {
+        x = math.Min(x, float64(d.Max))
+    }
+    return time.Duration(math.Max(x, float64(d.Min)))
+}
+
+// VirtualClock maps the run onto a day: the scenario begins at Start past
+// midnight and a real second is Speed virtual ones. Zero Speed is real time.
+type VirtualClock struct {
+    Start time.Duration `yaml:"start"`
+    Speed float64       `yaml:"speed"`
+}
+
+func (c VirtualClock) speed() float64 {
+    if c.Speed <= 0 {
+        return 1
+    }
+    return c.Speed
+}
+
+// TimeOfDay is the virtual time of day after elapsed real time.
+func (c VirtualClock) TimeOfDay(elapsed time.Duration) time.Duration {
+    virtual := c.Start + time.Duration(float64(elapsed)*c.speed())
+    return (virtual%(24*time.Hour) + 24*time.Hour) % (24 * time.Hour)
+}
+
+// real converts a virtual length of time to real time.
+func (c VirtualClock) real(d time.Duration) time.Duration {
+    return time.Duration(float64(d) / c.speed())
+}
+
+// diurnal is a curve's level at a time of day, relative to its peak:
+// one value per hour, interpolated in between. An empty curve is flat.
+func diurnal(curve []float64, timeOfDay time.Duration) float64 {
+    if len(curve) == 0 {
+        return 1
+    }
+    peak := 0.0
+    for _, v := range curve {
+        peak = math.Max(peak, v)
+    }
+    hours := timeOfDay.Hours()
+    hour := int(hours) % 24
+    frac := hours - math.Floor(hours)
+    level := curve[hour]*(1-frac) + curve[(hour+1)%24]*frac
+    return level / peak
+}
+
+func validateDiurnal(curve []float64) error {
+    if len(curve) == 0 {
+        return nil
+    }
+    if len(curve) != 24 {
+        return fmt.Errorf("diurnal curve has %d values, not one per hour", len(curve))
+    }
+    peak := 0.0
+    for _, v := range curve {
+        if v < 0 {
+            return errors.New("negative diurnal level")
+        }
+        peak = math.Max(peak, v)
+    }
+    if peak == 0 {
+        return errors.New("diurnal curve is zero all day")
+    }
+    return nil
+}
@@ -0,0 +1,175 @@
+// internal/simulator/replay.go
+package simulator
+
+import (
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "os"
+    "sort"
+    "sync"
+    "time"
+    "redditclone/pkg/client"
+    "google.golang.org/protobuf/encoding/protojson"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/reflect/protoreflect"
+    "google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// ReplayOptions controls how a trace is re-issued.
+type ReplayOptions struct {
+    // Speed scales the recorded timing: 1 is the original pace, 10 is ten
+    // times faster, and 0 or less sends requests as fast as dependencies
+    // allow.
+    Speed float64
+
+    // MaxInFlight caps outstanding requests. Defaults to 1000.
+    MaxInFlight int
+
+    Label string
+}
+
+// createdID is what a recorded create turned into during the replay.
+type createdID struct {
+    done chan struct{}
+    id   string // empty if the replayed create failed
+}
+
+// Replay re-issues the requests in a trace file against the engine. The
+// engine should be fresh: recorded usernames and subreddit names are reused.
+// Requests go out in recorded order; one that refers to an entity the trace
+// created waits for that create and uses the new ID.
+func Replay(engineClient *client.Client, path string, options ReplayOptions) (*Report, error) {
+    records, err := readTrace(path)
+    if err != nil {
+        return nil, err
+    }
+    if options.MaxInFlight <= 0 {
+        options.MaxInFlight = 1000
+    }
+
+    created := make(map[string]*createdID)
+    for _, record := range records {
+        if record.ID != "" {
+            created[record.ID] = &createdID{done: make(chan struct{})}
+        }
+    }
+
+    var (
+        mu       sync.Mutex
+        recorded = metrics{}
+        wg       sync.WaitGroup
+        slots    = make(chan struct{}, options.MaxInFlight)
+        started  = time.Now()
+    )
+
+    for _, record := range records {
+        msg, err := decodeMessage(record)
+        if err != nil {
+            return nil, err
+        }
+        renewIdempotencyKey(msg)
+
+        // Requests are measured from when they were due, so time spent
+        // waiting for a slot or a dependency counts against the engine
+        due := time.Now()
+        if options.Speed > 0 {
+            due = started.Add(time.Duration(float64(record.At) / options.Speed))
+            time.Sleep(time.Until(due))
+        }
+        slots <- struct{}{}
+        wg.Add(1)
+
+        go func(record traceRecord, msg proto.Message, due time.Time) {
+            defer wg.Done()
+            defer func() { <-slots }()
+
+            // Creates are dispatched before anything that refers to them,
+            // so waiting here cannot deadlock
+            for _, field := range idFields(msg) {
+                if target, exists := created[*field]; exists {
+                    <-target.done
+                    if target.id != "" {
+                        *field = target.id
+                    }
+                }
+            }
+
+            sent := time.Now()
+            response, err := engineClient.Result(engineClient.Future(msg).Result())
+            latency := time.Since(due)
+
+            if target, exists := created[record.ID]; exists {
+                if err == nil {
+                    target.id = response.Id
+                }
+                close(target.done)
+            }
+
+            mu.Lock()
+            recorded.record(opName(msg), latency, sent.Sub(due), err)
+            mu.Unlock()
+        }(record, msg, due)
+    }
+    wg.Wait()
+
+    elapsed := time.Since(started)
+    report := &Report{
+        Label:           options.Label,
+        StartedAt:       started,
+        DurationSeconds: elapsed.Seconds(),
+        Operations:      operationReports(recorded, elapsed),
+    }
+    logOperations(fmt.Sprintf("Replayed %d requests in %s", len(records), elapsed.Round(time.Millisecond)), report.Operations)
+    return report, nil
+}
+
+func readTrace(path string) ([]traceRecord, error) {
+    file, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+
+    records := make([]traceRecord, 0)
+    scanner := bufio.NewScanner(file)
+    scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+    for line := 1; scanner.Scan(); line++ {
+        var record traceRecord
+        if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+            return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+        }
+        records = append(records, record)
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+
+    sort.SliceStable(records, func(i, j int) bool {
+        return records[i].At < records[j].At
+    })
+    return records, nil
+}
+
+// renewIdempotencyKey gives a create a key of its own, so replaying a trace
+// on the engine it was recorded against creates everything again instead
+// of getting the recorded responses back.
+func renewIdempotencyKey(msg proto.Message) {
+    m := msg.ProtoReflect()
+    if field := m.Descriptor().Fields().ByName("idempotency_key"); field != nil {
+        m.Set(field, protoreflect.ValueOfString(client.NewIdempotencyKey()))
+    }
+}
+
+func decodeMessage(record traceRecord) (proto.Message, error) {
+    messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(record.Type))
+    if err != nil {
+        return nil, fmt.Errorf("trace record of type %q: %w", record.Type, err)
+    }
+
+    msg := messageType.New().Interface()
+    if err := protojson.Unmarshal(record.Message, msg); err != nil {
+        return nil, fmt.Errorf("trace record of type %q: %w", record.Type, err)
+    }
+    return msg, nil
+}
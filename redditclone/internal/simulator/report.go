@@ -0,0 +1,138 @@
+// internal/simulator/report.go
+package simulator
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "log"
+    "os"
+    "sort"
+    "strconv"
+    "time"
+)
+
+// Report is the machine-readable summary of a run, written as JSON or CSV
+// so runs against different engine versions can be compared.
+type Report struct {
+    Label           string            `json:"label,omitempty"`
+    Scenario        string            `json:"scenario,omitempty"`
+    StartedAt       time.Time         `json:"started_at"`
+    DurationSeconds float64           `json:"duration_seconds"`
+    Users           int               `json:"users"`
+    Subreddits      int               `json:"subreddits"`
+    Posts           int32             `json:"posts"`
+    Comments        int32             `json:"comments"`
+    Messages        int32             `json:"messages"`
+    Operations      []OperationReport `json:"operations"`
+
+    // Open-loop runs only: the rate asked for, how many actions were
+    // scheduled, and whether the engine fell behind at any point.
+    TargetRate float64 `json:"target_rate,omitempty"`
+    Scheduled  uint64  `json:"scheduled,omitempty"`
+    Behind     bool    `json:"behind,omitempty"`
+
+    // Verified runs only: every way the engine differed from the
+    // simulator's model of it.
+    Verified    bool     `json:"verified,omitempty"`
+    Divergences []string `json:"divergences,omitempty"`
+}
+
+// OperationReport summarises one operation. Latencies are in milliseconds;
+// LagP99Ms is how late the slowest requests went out against their schedule.
+type OperationReport struct {
+    Name       string  `json:"name"`
+    Count      uint64  `json:"count"`
+    Successes  uint64  `json:"successes"`
+    Failures   uint64  `json:"failures"`
+    Timeouts   uint64  `json:"timeouts"`
+    Overloaded uint64  `json:"overloaded"`
+    Throughput float64 `json:"throughput_per_second"`
+    MeanMs     float64 `json:"mean_ms"`
+    P50Ms      float64 `json:"p50_ms"`
+    P90Ms      float64 `json:"p90_ms"`
+    P99Ms      float64 `json:"p99_ms"`
+    MaxMs      float64 `json:"max_ms"`
+    LagP99Ms   float64 `json:"lag_p99_ms"`
+}
+
+func operationReports(m metrics, elapsed time.Duration) []OperationReport {
+    reports := make([]OperationReport, 0, len(m))
+    for name, stats := range m {
+        reports = append(reports, OperationReport{
+            Name:       name,
+            Count:      stats.Count,
+            Successes:  stats.Successes,
+            Failures:   stats.Failures,
+            Timeouts:   stats.Timeouts,
+            Overloaded: stats.Overloaded,
+            Throughput: float64(stats.Count) / elapsed.Seconds(),
+            MeanMs:     milliseconds(stats.Latency.mean()),
+            P50Ms:      milliseconds(stats.Latency.quantile(0.50)),
+            P90Ms:      milliseconds(stats.Latency.quantile(0.90)),
+            P99Ms:      milliseconds(stats.Latency.quantile(0.99)),
+            MaxMs:      milliseconds(stats.Latency.Max),
+            LagP99Ms:   milliseconds(stats.Lag.quantile(0.99)),
+        })
+    }
+    sort.Slice(reports, func(i, j int) bool {
+        return reports[i].Name < reports[j].Name
+    })
+    return reports
+}
+
+func milliseconds(d time.Duration) float64 {
+    return float64(d) / float64(time.Millisecond)
+}
+
+// logOperations prints one line per operation.
+func logOperations(title string, reports []OperationReport) {
+    log.Println(title)
+    log.Printf("%-16s %9s %9s %8s %8s %8s %9s %9s %9s %9s %9s %9s",
+        "operation", "count", "ok", "failed", "timeout", "overload", "ops/s", "p50 ms", "p90 ms", "p99 ms", "max ms", "lag p99")
+    for _, op := range reports {
+        log.Printf("%-16s %9d %9d %8d %8d %8d %9.1f %9.2f %9.2f %9.2f %9.2f %9.2f",
+            op.Name, op.Count, op.Successes, op.Failures, op.Timeouts, op.Overloaded, op.Throughput,
+            op.P50Ms, op.P90Ms, op.P99Ms, op.MaxMs, op.LagP99Ms)
+    }
+}
+
+func (r *Report) WriteJSON(path string) error {
+    data, err := json.MarshalIndent(r, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// WriteCSV writes one row per operation, each carrying the run's label and
+// duration so files from several runs can be concatenated.
+func (r *Report) WriteCSV(path string) error {
+    file, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    w := csv.NewWriter(file)
+    w.Write([]string{"label", "started_at", "duration_seconds", "users", "operation",
+        "count", "successes", "failures", "timeouts", "overloaded", "throughput_per_second",
+        "mean_ms", "p50_ms", "p90_ms", "p99_ms", "max_ms", "lag_p99_ms"})
+
+    float := func(f float64) string {
+        return strconv.FormatFloat(f, 'f', 3, 64)
+    }
+    for _, op := range r.Operations {
+        w.Write([]string{r.Label, r.StartedAt.Format(time.RFC3339), float(r.DurationSeconds),
+            strconv.Itoa(r.Users), op.Name,
+            fmt.Sprint(op.Count), fmt.Sprint(op.Successes), fmt.Sprint(op.Failures), fmt.Sprint(op.Timeouts),
+            fmt.Sprint(op.Overloaded),
+            float(op.Throughput), float(op.MeanMs), float(op.P50Ms), float(op.P90Ms), float(op.P99Ms), float(op.MaxMs), float(op.LagP99Ms)})
+    }
+
+    w.Flush()
+    if err := w.Error(); err != nil {
+        return err
+    }
+    return file.Close()
+}
@@ -0,0 +1,105 @@
+// internal/simulator/requests.go
+package simulator
+
+import (
+    "errors"
+    "math/rand"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "redditclone/internal/messages"
+    "redditclone/pkg/client"
+    "redditclone/pkg/clock"
+    "google.golang.org/protobuf/proto"
+)
+
+// requests sends engine requests for one actor, counts the ones still
+// outstanding and records how each one went.
+type requests struct {
+    client   *client.Client
+    clock    clock.Clock
+    inFlight int
+    metrics  metrics
+    trace    *Trace    // nil unless the run is traced
+    due      time.Time // when the next request should have gone out, if scheduled
+    answered uint64    // scheduled requests answered since the last metrics delta
+
+    backoff      time.Duration // held off for after the last overload in a row
+    backoffUntil time.Time     // jittered, so actors turned away together don't retry together
+    rng          *rand.Rand    // the actor's, so the seed decides the jitter too
+}
+
+// A manager whose mailbox is full answers OVERLOADED, and one limiting its
+// callers RATE_LIMITED. An actor told either holds off for
+// minOverloadBackoff, doubling with each overload in a row up to
+// maxOverloadBackoff, so the engine gets to drain its queues, and then
+// sends the request again, up to overloadRetries times.
+const (
+    minOverloadBackoff = 100 * time.Millisecond
+    maxOverloadBackoff = 5 * time.Second
+    overloadRetries    = 5
+)
+
+// send sends msg to the engine and runs then with the outcome on the actor's
+// goroutine. A scheduled request's latency counts from when it was due, so
+// a slow engine can't hide behind a late schedule. Each attempt at an
+// overloaded request is recorded on its own; only the last is answered.
+func (r *requests) send(context actor.Context, msg interface{}, then func(*messages.OperationResponse, error)) {
+    r.inFlight++
+    due, scheduled := r.clock.Now(), !r.due.IsZero()
+    if scheduled {
+        due, r.due = r.due, time.Time{}
+    }
+    r.attempt(context, msg, due, 0, func(response *messages.OperationResponse, err error) {
+        r.inFlight--
+        if scheduled {
+            r.answered++
+        }
+        then(response, err)
+    })
+}
+
+func (r *requests) attempt(context actor.Context, msg interface{}, due time.Time, retries int, then func(*messages.OperationResponse, error)) {
+    start := r.clock.Now()
+    context.ReenterAfter(r.client.Future(msg), func(res interface{}, err error) {
+        response, err := r.client.Result(res, err)
+        r.backOff(err)
+        r.metrics.record(opName(msg), r.clock.Now().Sub(due), start.Sub(due), err)
+        if r.trace != nil {
+            r.trace.record(msg.(proto.Message), start, response, err)
+        }
+        if !overloaded(err) || retries == overloadRetries {
+            then(response, err)
+            return
+        }
+
+        // Wait out the backoff without holding up the actor. The future
+        // has no timeout of its own, as the clock's timer completes it.
+        wait := actor.NewFuture(context.ActorSystem(), -1)
+        system := context.ActorSystem()
+        r.clock.AfterFunc(r.backoffUntil.Sub(r.clock.Now()), func() {
+            system.Root.Send(wait.PID(), struct{}{})
+        })
+        context.ReenterAfter(wait, func(interface{}, error) {
+            r.attempt(context, msg, r.clock.Now(), retries+1, then)
+        })
+    })
+}
+
+func (r *requests) backOff(err error) {
+    if overloaded(err) {
+        r.backoff = min(max(2*r.backoff, minOverloadBackoff), maxOverloadBackoff)
+        r.backoffUntil = r.clock.Now().Add(r.backoff/2 + time.Duration(r.rng.Int63n(int64(r.backoff/2))))
+    } else if err == nil {
+        r.backoff = 0
+    }
+}
+
+// overloaded reports whether err asks the caller to back off.
+func overloaded(err error) bool {
+    return errors.Is(err, client.ErrOverloaded) || errors.Is(err, client.ErrRateLimited)
+}
+
+// backingOff reports whether the actor should hold off on new requests.
+func (r *requests) backingOff() bool {
+    return r.clock.Now().Before(r.backoffUntil)
+}
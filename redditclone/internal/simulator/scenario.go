@@ -0,0 +1,357 @@
+// internal/simulator/scenario.go
+package simulator
+
+import (
+    "bytes"
+    "embed"
+    "errors"
+    "fmt"
+    "io"
+    "io/fs"
+    "os"
+    "path"
+    "sort"
+    "strings"
+    "time"
+    "gopkg.in/yaml.v3"
+)
+
+//go:embed scenarios/*.yaml
+var bundled embed.FS
+
+// Scenario describes how a run unfolds: a sequence of phases, each with its
+// own number of active users and their behaviour. Phases follow the
+// simulator's clock, the wall clock unless its Config says otherwise, from
+// the moment users begin, pauses included; Clock puts that on a virtual day
+// for diurnal curves.
+type Scenario struct {
+    Name        string
+    Description string
+    Clock       VirtualClock
+    Phases      []Phase
+}
+
+// Phase is one stretch of a scenario. Active users move linearly from the
+// previous phase's count (zero before the first) to Users over Ramp, then
+// hold. A Duration of zero runs until the simulation is stopped and is only
+// allowed for the last phase.
+type Phase struct {
+    Name     string
+    Duration time.Duration
+    Users    int
+    Ramp     time.Duration
+    Behavior Behavior
+}
+
+// Behavior is how active users act. In a scenario file each phase starts
+// from the scenario's behaviour and overrides what it lists.
+type Behavior struct {
+    Actions      ActionWeights `yaml:"actions"`
+    UpvoteRatio  float64       `yaml:"upvote_ratio"` // share of votes that are upvotes
+    ReplyRatio   float64       `yaml:"reply_ratio"`  // share of comments that reply to a comment
+    Focus        float64       `yaml:"focus"`        // share of comments and votes on the hot post
+    Connectivity Connectivity  `yaml:"connectivity"`
+    Content      ContentSizes  `yaml:"content"`
+    Personas     []Persona     `yaml:"personas"` // empty: everyone acts on Actions
+    Diurnal      []float64     `yaml:"diurnal"`  // activity by virtual hour; empty is flat
+}
+
+// ActionWeights are the relative odds of each action on a user's turn.
+type ActionWeights struct {
+    Join    int `yaml:"join"`
+    Post    int `yaml:"post"`
+    Repost  int `yaml:"repost"`
+    Comment int `yaml:"comment"`
+    Vote    int `yaml:"vote"`
+    Message int `yaml:"message"`
+    Read    int `yaml:"read"` // the feed, a post or its comments
+}
+
+func (a ActionWeights) weights() []int {
+    return []int{a.Join, a.Post, a.Repost, a.Comment, a.Vote, a.Message, a.Read}
+}
+
+func (a ActionWeights) validate() []error {
+    var errs []error
+    total := 0
+    for _, weight := range a.weights() {
+        if weight < 0 {
+            errs = append(errs, errors.New("negative action weight"))
+        }
+        total += weight
+    }
+    if total <= 0 {
+        errs = append(errs, errors.New("action weights add up to zero"))
+    }
+    return errs
+}
+
+// Connectivity is how users come and go. With Session and Gap, users stay
+// online and away for lengths drawn from them; otherwise Disconnect and
+// Reconnect are the chances, on each turn, that a user goes offline or
+// comes back. Either way, a user coming back catches up on its messages
+// and feed.
+type Connectivity struct {
+    Disconnect float64       `yaml:"disconnect"`
+    Reconnect  float64       `yaml:"reconnect"`
+    Session    Distribution  `yaml:"session"`
+    Gap        Distribution  `yaml:"gap"`
+}
+
+// ContentSizes shape generated text: its typical lengths in characters,
+// zero for the default, and what goes into it.
+type ContentSizes struct {
+    Title      int     `yaml:"title"`
+    Post       int     `yaml:"post"`
+    Comment    int     `yaml:"comment"`
+    Message    int     `yaml:"message"`
+    Spread     float64 `yaml:"spread"`     // lognormal spread of lengths around the sizes; zero is exact
+    Links      float64 `yaml:"links"`      // share of posts, comments and messages with a link
+    Mentions   float64 `yaml:"mentions"`   // share that mention another user
+    Duplicates float64 `yaml:"duplicates"` // share of posts copying an earlier post word for word
+}
+
+// DefaultBehavior is what users do when a scenario doesn't say otherwise.
+func DefaultBehavior() Behavior {
+    return Behavior{
+        Actions:      ActionWeights{Join: 1, Post: 10, Repost: 10, Comment: 10, Vote: 10, Message: 10},
+        UpvoteRatio:  0.7,
+        ReplyRatio:   0.3,
+        Connectivity: Connectivity{Disconnect: 0.1, Reconnect: 0.1},
+        Content:      ContentSizes{Spread: 0.5, Links: 0.05, Mentions: 0.05, Duplicates: 0.02},
+    }
+}
+
+// SteadyScenario keeps all users active with the default behaviour until
+// the simulation is stopped.
+func SteadyScenario(users int) *Scenario {
+    return &Scenario{
+        Name:   "steady",
+        Phases: []Phase{{Name: "steady", Users: users, Behavior: DefaultBehavior()}},
+    }
+}
+
+// Population is the number of users the scenario needs at its busiest.
+func (s *Scenario) Population() int {
+    population := 0
+    for _, p := range s.Phases {
+        population = max(population, p.Users)
+    }
+    return population
+}
+
+// Duration is the scenario's length, or zero if its last phase is open.
+func (s *Scenario) Duration() time.Duration {
+    var total time.Duration
+    for _, p := range s.Phases {
+        if p.Duration == 0 {
+            return 0
+        }
+        total += p.Duration
+    }
+    return total
+}
+
+// At returns the phase running after elapsed and how many users should be
+// active then. Past the end, the last phase holds.
+func (s *Scenario) At(elapsed time.Duration) (int, int) {
+    previous := 0
+    for i, p := range s.Phases {
+        if elapsed < p.Duration || p.Duration == 0 || i == len(s.Phases)-1 {
+            if elapsed >= p.Ramp {
+                return i, p.Users
+            }
+            return i, previous + int(float64(p.Users-previous)*float64(elapsed)/float64(p.Ramp))
+        }
+        elapsed -= p.Duration
+        previous = p.Users
+    }
+    return 0, 0
+}
+
+// Validate reports everything wrong with the scenario at once.
+func (s *Scenario) Validate() error {
+    var errs []error
+    if len(s.Phases) == 0 {
+        errs = append(errs, errors.New("no phases"))
+    }
+    for i, p := range s.Phases {
+        where := fmt.Sprintf("phase %d", i+1)
+        if p.Name != "" {
+            where += fmt.Sprintf(" (%s)", p.Name)
+        }
+        fail := func(format string, args ...interface{}) {
+            errs = append(errs, fmt.Errorf("%s: %s", where, fmt.Sprintf(format, args...)))
+        }
+
+        switch {
+        case p.Duration < 0:
+            fail("negative duration")
+        case p.Duration == 0 && i < len(s.Phases)-1:
+            fail("only the last phase may run until stopped")
+        }
+        if p.Users < 0 {
+            fail("negative users")
+        }
+        if p.Ramp < 0 {
+            fail("negative ramp")
+        } else if p.Duration > 0 && p.Ramp > p.Duration {
+            fail("ramp %s is longer than the phase", p.Ramp)
+        }
+        for _, err := range p.Behavior.validate() {
+            fail("%v", err)
+        }
+    }
+    if len(s.Phases) > 0 && s.Population() == 0 {
+        errs = append(errs, errors.New("no phase has any users"))
+    }
+    return errors.Join(errs...)
+}
+
+func (b *Behavior) validate() []error {
+    var errs []error
+    if len(b.Personas) == 0 {
+        errs = append(errs, b.Actions.validate()...)
+    }
+    shares := 0.0
+    for i := range b.Personas {
+        errs = append(errs, b.Personas[i].validate()...)
+        shares += b.Personas[i].Share
+    }
+    if len(b.Personas) > 0 && shares <= 0 {
+        errs = append(errs, errors.New("persona shares add up to zero"))
+    }
+    if err := validateDiurnal(b.Diurnal); err != nil {
+        errs = append(errs, err)
+    }
+
+    ratios := map[string]float64{
+        "upvote_ratio":            b.UpvoteRatio,
+        "reply_ratio":             b.ReplyRatio,
+        "focus":                   b.Focus,
+        "connectivity.disconnect": b.Connectivity.Disconnect,
+        "connectivity.reconnect":  b.Connectivity.Reconnect,
+        "content.links":           b.Content.Links,
+        "content.mentions":        b.Content.Mentions,
+        "content.duplicates":      b.Content.Duplicates,
+    }
+    names := make([]string, 0, len(ratios))
+    for name := range ratios {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        if ratio := ratios[name]; ratio < 0 || ratio > 1 {
+            errs = append(errs, fmt.Errorf("%s %v is not between 0 and 1", name, ratio))
+        }
+    }
+
+    if b.Connectivity.Session.set() != b.Connectivity.Gap.set() {
+        errs = append(errs, errors.New("connectivity session and gap go together"))
+    }
+    for _, d := range []Distribution{b.Connectivity.Session, b.Connectivity.Gap} {
+        if err := d.validate(); err != nil {
+            errs = append(errs, fmt.Errorf("connectivity: %w", err))
+        }
+    }
+
+    c := b.Content
+    if c.Title < 0 || c.Post < 0 || c.Comment < 0 || c.Message < 0 {
+        errs = append(errs, errors.New("negative content size"))
+    }
+    if c.Spread < 0 {
+        errs = append(errs, errors.New("negative content spread"))
+    }
+    return errs
+}
+
+// scenarioFile is the layout of a scenario file. It is decoded twice: with
+// phase behaviour as a Behavior to check the fields, then as a node so it
+// can be decoded over the scenario's.
+type scenarioFile[B any] struct {
+    Name        string   `yaml:"name"`
+    Description string       `yaml:"description"`
+    Clock       VirtualClock `yaml:"clock"`
+    Behavior    Behavior     `yaml:"behavior"`
+    Phases      []struct {
+        Name     string        `yaml:"name"`
+        Duration time.Duration `yaml:"duration"`
+        Users    int           `yaml:"users"`
+        Ramp     time.Duration `yaml:"ramp"`
+        Behavior B             `yaml:"behavior"`
+    } `yaml:"phases"`
+}
+
+// LoadScenario reads and validates a scenario file, YAML or JSON. A name
+// that isn't a file is looked up among the bundled scenarios.
+func LoadScenario(name string) (*Scenario, error) {
+    data, err := os.ReadFile(name)
+    if errors.Is(err, fs.ErrNotExist) {
+        data, err = bundled.ReadFile(path.Join("scenarios", name+".yaml"))
+        if err != nil {
+            return nil, fmt.Errorf("no scenario file %s and no bundled scenario of that name (have %s)",
+                name, strings.Join(BundledScenarios(), ", "))
+        }
+    } else if err != nil {
+        return nil, err
+    }
+
+    s, err := ParseScenario(data)
+    if err != nil {
+        return nil, fmt.Errorf("scenario %s: %w", name, err)
+    }
+    return s, nil
+}
+
+// ParseScenario decodes and validates a scenario. Unknown fields are errors
+// so typos don't silently fall back to defaults.
+func ParseScenario(data []byte) (*Scenario, error) {
+    var checked scenarioFile[Behavior]
+    decoder := yaml.NewDecoder(bytes.NewReader(data))
+    decoder.KnownFields(true)
+    if err := decoder.Decode(&checked); errors.Is(err, io.EOF) {
+        return nil, errors.New("empty scenario")
+    } else if err != nil {
+        return nil, err
+    }
+
+    file := scenarioFile[yaml.Node]{Behavior: DefaultBehavior()}
+    if err := yaml.Unmarshal(data, &file); err != nil {
+        return nil, err
+    }
+
+    s := &Scenario{Name: file.Name, Description: file.Description, Clock: file.Clock}
+    for i, p := range file.Phases {
+        behavior := file.Behavior
+        if !p.Behavior.IsZero() {
+            if err := p.Behavior.Decode(&behavior); err != nil {
+                return nil, fmt.Errorf("phase %d: %w", i+1, err)
+            }
+        }
+        for j := range behavior.Personas {
+            behavior.Personas[j].fillStandard()
+        }
+        s.Phases = append(s.Phases, Phase{
+            Name:     p.Name,
+            Duration: p.Duration,
+            Users:    p.Users,
+            Ramp:     p.Ramp,
+            Behavior: behavior,
+        })
+    }
+
+    if err := s.Validate(); err != nil {
+        return nil, err
+    }
+    return s, nil
+}
+
+// BundledScenarios lists the scenarios built into the simulator.
+func BundledScenarios() []string {
+    entries, _ := bundled.ReadDir("scenarios")
+    names := make([]string, 0, len(entries))
+    for _, entry := range entries {
+        names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+    }
+    return names
+}
@@ -0,0 +1,712 @@
+// internal/simulator/simulator.go
+package simulator
+
+import (
+    "fmt"
+    "log"
+    "math/rand"
+    "strings"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/scheduler"
+    "redditclone/internal/messages"
+    "redditclone/pkg/client"
+    "redditclone/pkg/clock"
+    "google.golang.org/protobuf/proto"
+)
+
+// Config describes the simulated population.
+type Config struct {
+    // NumUsers is ignored when there is a Scenario; its busiest phase sets
+    // the population.
+    NumUsers int
+
+    // Scenario is how the run unfolds. Defaults to SteadyScenario.
+    Scenario *Scenario
+
+    // NumSubreddits defaults to one per 20 users.
+    NumSubreddits int
+
+    // ZipfExponent skews subreddit sizes: the subreddit at rank k starts with
+    // NumUsers/k^ZipfExponent members.
+    ZipfExponent float64
+
+    // TickInterval is how often each connected user acts. Defaults to
+    // 100ms.
+    TickInterval time.Duration
+
+    // Rate, if positive, makes the run open-loop: the simulator schedules
+    // Rate actions a second across the active users whether or not earlier
+    // requests have been answered, and latency counts from when each was
+    // due. Users don't tick or go offline. Zero is closed-loop: each user
+    // waits for its last request before acting again.
+    Rate float64
+
+    // Seed drives every random choice: the simulator's and each user's.
+    // Zero picks one from the clock; it is logged so the run can be
+    // repeated. Users still see each other's content as timing allows, so
+    // only replaying a trace reproduces a run request for request.
+    Seed int64
+
+    // TracePath, if set, is where every request is recorded for Replay.
+    TracePath string
+
+    // ReportInterval is how often metrics are logged during the run.
+    // Defaults to 10s; negative turns the periodic summary off.
+    ReportInterval time.Duration
+
+    // ReportJSON and ReportCSV, if set, are where the final Report is
+    // written. Label names the run in them.
+    ReportJSON string
+    ReportCSV  string
+    Label      string
+
+    // Coordinator, if set, makes this simulator a worker in a distributed
+    // run: it simulates the users of its Partition, the coordinator creates
+    // the subreddits and says when to begin, and stats stream back to it.
+    // Rate is then this worker's share. Users only see posts and comments
+    // from their own worker's catalog.
+    Coordinator *actor.PID
+    Partition   Partition
+
+    // Verify keeps a model of what the engine should hold, built from the
+    // answers users get, and once users have stopped reads everything back
+    // and reports each divergence. OnVerified, if set, is given them. Not
+    // for distributed runs, where no one simulator sees every message.
+    Verify     bool
+    OnVerified func(divergences []string)
+
+    // Clock is the run's clock: phases, sessions, diurnal curves, the
+    // users' turns, the open-loop pace, overload backoff, pauses, latencies
+    // and the report's duration all follow it. Defaults to the system
+    // clock; a clock.Fake lets a test step through a run.
+    Clock clock.Clock
+
+    // OnFinish, if set, is called once the simulator has stopped.
+    OnFinish func()
+}
+
+type phase int
+
+const (
+    idle phase = iota
+    registering
+    creatingSubreddits
+    awaitingBegin // workers only, until the coordinator says to begin
+    running
+    stopped
+    verifying
+)
+
+// recentLimit bounds the posts and comments kept for the catalog.
+const recentLimit = 256
+
+// catalogInterval is how often users get a fresh catalog, and how often the
+// scenario's active users are adjusted.
+const catalogInterval = time.Second
+
+// tick is sent to each user every TickInterval while it runs.
+type tick struct{}
+
+// publish is sent to the simulator every catalogInterval.
+type publish struct{}
+
+// summarize is sent to the simulator every ReportInterval.
+type summarize struct{}
+
+// paceInterval is how often an open-loop run hands out the actions that
+// have come due.
+const paceInterval = 10 * time.Millisecond
+
+// pace is sent to the simulator every paceInterval in open-loop runs.
+type pace struct{}
+
+// SimulatorActor coordinates the simulated users. It spawns one userActor
+// per user, creates the subreddits once they have registered, hands each
+// user its starting subreddits, and then aggregates what they report. Users
+// learn about each other's posts and comments from a catalog it publishes
+// every second.
+type SimulatorActor struct {
+    UserIDs     []string // this simulator's own users
+    Communities []*community
+    Users       map[string]*actor.PID // live users, by PID ID
+    Client      *client.Client
+    Config      Config
+    Stats       *messages.SimulationStats
+
+    requests
+    phase          phase
+    stopping       bool
+    stopper        *actor.PID // who asked to stop, answered with the stats
+    unregistered   int
+    userPIDs       map[string]*actor.PID // by user ID
+    recentPosts    []catalogPost
+    recentComments []catalogComment
+    stopPublishing scheduler.CancelFunc
+    stopSummaries  scheduler.CancelFunc
+    startedAt      time.Time
+    rng            *rand.Rand
+    created        []*community // in creation order, nil where it failed
+    begunAt        time.Time    // when the scenario's clock started
+    step           int          // the scenario phase running
+    active         int          // users active, the first of UserIDs
+    hot            string       // post the phase focuses on
+    stopPacing     scheduler.CancelFunc
+    paceStart      time.Time // when action 0 was due, moved on by pauses
+    pausedAt       time.Time // zero unless paused
+    scheduled      uint64    // open-loop actions handed out
+    answered       uint64    // of those, answered or found idle by users
+    behind         bool      // too many scheduled actions outstanding
+    everBehind     bool
+    everyone       []string // every user in the run, for direct messages
+    model          *model   // verified runs only
+    divergences    []string
+}
+
+// Props returns the props for a simulator. Users that crash are restarted
+// with their session intact, up to three times in ten seconds.
+func Props(config Config, engineClient *client.Client) *actor.Props {
+    return actor.PropsFromProducer(func() actor.Actor {
+        return NewSimulatorActor(config, engineClient)
+    }, actor.WithSupervisor(actor.NewOneForOneStrategy(3, 10*time.Second, actor.DefaultDecider)))
+}
+
+func NewSimulatorActor(config Config, engineClient *client.Client) *SimulatorActor {
+    if config.Scenario == nil {
+        config.Scenario = SteadyScenario(config.NumUsers)
+    } else {
+        config.NumUsers = config.Partition.size(config.Scenario.Population())
+    }
+    if config.NumSubreddits < 1 {
+        config.NumSubreddits = max(config.NumUsers/20, 1)
+    }
+    if config.TickInterval <= 0 {
+        config.TickInterval = 100 * time.Millisecond
+    }
+    if config.ReportInterval == 0 {
+        config.ReportInterval = 10 * time.Second
+    }
+    if config.Seed == 0 {
+        config.Seed = time.Now().UnixNano()
+    }
+    if config.Coordinator != nil {
+        config.Verify = false
+    }
+    if config.Clock == nil {
+        config.Clock = clock.Real{}
+    }
+
+    rng := rand.New(rand.NewSource(config.Seed))
+    state := &SimulatorActor{
+        UserIDs:     make([]string, 0),
+        Communities: make([]*community, 0),
+        Users:       make(map[string]*actor.PID),
+        Client:      engineClient,
+        Config:      config,
+        Stats:       &messages.SimulationStats{},
+        requests:    requests{client: engineClient, clock: config.Clock, metrics: metrics{}, rng: rng},
+        userPIDs:    make(map[string]*actor.PID),
+        rng:         rng,
+    }
+    if config.Verify {
+        state.model = newModel()
+    }
+    return state
+}
+
+func (state *SimulatorActor) Receive(context actor.Context) {
+    switch msg := context.Message().(type) {
+    case *actor.Started:
+        log.Println("SimulatorActor started")
+        if state.Config.Coordinator != nil {
+            context.Watch(state.Config.Coordinator)
+        }
+
+    case *messages.StartSimulation:
+        if state.phase != idle {
+            return
+        }
+        log.Printf("Starting scenario %s with %d users, seed %d",
+            state.Config.Scenario.Name, state.Config.NumUsers, state.Config.Seed)
+        state.startedAt = state.Config.Clock.Now()
+        if state.Config.TracePath != "" {
+            trace, err := CreateTrace(state.Config.TracePath, state.startedAt)
+            if err != nil {
+                log.Printf("Not tracing: %v", err)
+            } else {
+                state.trace = trace
+            }
+        }
+        if state.Config.ReportInterval > 0 {
+            state.stopSummaries = sendRepeatedly(state.Config.Clock, context,
+                state.Config.ReportInterval, state.Config.ReportInterval, &summarize{})
+        }
+        state.spawnUsers(context)
+
+    case *registered:
+        state.unregistered--
+        if msg.UserID == "" {
+            log.Printf("Failed to register user %s", msg.Username)
+            context.Stop(context.Sender())
+        } else {
+            state.UserIDs[state.Config.Partition.local(msg.Index)] = msg.UserID
+            state.userPIDs[msg.UserID] = context.Sender()
+            state.Stats.RegisteredUsers++
+        }
+        state.advance(context)
+
+    case *activity:
+        state.record(msg)
+
+    case *metricsDelta:
+        state.metrics.merge(msg.Metrics)
+        state.answered += msg.Answered + msg.Idle
+
+    case *pace:
+        state.pace(context)
+
+    case *publish:
+        state.followScenario(context)
+        state.publishCatalog(context)
+        state.checkPace()
+        state.reportToCoordinator(context, false)
+
+    case *messages.BeginWork:
+        state.beginWork(context, msg)
+
+    case *start:
+        if state.phase == awaitingBegin {
+            state.beginUsers(context)
+        }
+
+    case *summarize:
+        elapsed := state.Config.Clock.Now().Sub(state.startedAt)
+        logOperations(fmt.Sprintf("Metrics after %s", elapsed.Round(time.Second)),
+            operationReports(state.metrics, elapsed))
+
+    // Users pause and resume themselves; users still registering pick it
+    // up before they start
+    case *messages.PauseSimulation:
+        log.Println("Pausing simulation")
+        if state.pausedAt.IsZero() {
+            state.pausedAt = state.Config.Clock.Now()
+        }
+        state.broadcast(context, msg)
+
+    case *messages.ResumeSimulation:
+        log.Println("Resuming simulation")
+        // The open-loop schedule carries on from where it paused
+        if !state.pausedAt.IsZero() {
+            state.paceStart = state.paceStart.Add(state.Config.Clock.Now().Sub(state.pausedAt))
+            state.pausedAt = time.Time{}
+        }
+        state.broadcast(context, msg)
+
+    case *messages.StopSimulation:
+        if state.stopping {
+            return
+        }
+        state.stopping = true
+        state.stopper = context.Sender()
+        log.Printf("Stopping simulation, waiting for %d users", len(state.Users))
+        state.phase = stopped
+        state.stopTimers()
+        state.broadcast(context, msg)
+        state.advance(context)
+
+    case *verified:
+        state.divergences = msg.Divergences
+        logDivergences(msg.Divergences)
+        if state.Config.OnVerified != nil {
+            state.Config.OnVerified(msg.Divergences)
+        }
+        state.finish(context)
+
+    case *userDone:
+        delete(state.Users, context.Sender().Id)
+        state.advance(context)
+
+    case *actor.Terminated:
+        if msg.Who.Equal(state.Config.Coordinator) {
+            log.Printf("Lost the coordinator, stopping")
+            context.Send(context.Self(), &messages.StopSimulation{})
+            return
+        }
+        // A user that failed for good never says it is done
+        delete(state.Users, msg.Who.Id)
+        state.advance(context)
+
+    case *actor.Stopping:
+        state.stopTimers()
+
+    case *actor.Stopped, *actor.Restarting:
+
+    default:
+        log.Printf("Received unknown message: %v", msg)
+    }
+}
+
+// request is requests.send for the simulator's own setup requests.
+func (state *SimulatorActor) request(context actor.Context, msg interface{}, then func(*messages.OperationResponse, error)) {
+    state.send(context, msg, func(response *messages.OperationResponse, err error) {
+        then(response, err)
+        state.advance(context)
+    })
+}
+
+// advance moves to the next phase once the current one is done.
+func (state *SimulatorActor) advance(context actor.Context) {
+    switch state.phase {
+    case registering:
+        if state.unregistered > 0 {
+            return
+        }
+
+        // Keep users in index order so seeded runs hand out the same
+        // subreddits whatever order registrations finished in
+        registeredIDs := make([]string, 0, len(state.UserIDs))
+        for _, userID := range state.UserIDs {
+            if userID != "" {
+                registeredIDs = append(registeredIDs, userID)
+            }
+        }
+        state.UserIDs = registeredIDs
+        state.everyone = registeredIDs
+        log.Printf("User registration phase complete. Registered %d users", len(state.UserIDs))
+        if state.Config.Coordinator != nil {
+            state.ready(context)
+            return
+        }
+        if len(state.UserIDs) == 0 {
+            log.Printf("No users registered, stopping simulation")
+            state.phase = stopped
+            return
+        }
+        state.createSubreddits(context)
+
+    case creatingSubreddits:
+        if state.inFlight == 0 {
+            state.beginUsers(context)
+        }
+
+    case stopped:
+        if state.stopping && state.inFlight == 0 && len(state.Users) == 0 {
+            if state.model != nil {
+                state.verify(context)
+                return
+            }
+            state.finish(context)
+        }
+    }
+}
+
+// verify checks the engine against the model off the actor's goroutine,
+// since it takes a read per subreddit, post and user, and finishes once the
+// divergences are back.
+func (state *SimulatorActor) verify(context actor.Context) {
+    state.phase = verifying
+    subredditIDs := make([]string, len(state.Communities))
+    for i, c := range state.Communities {
+        subredditIDs[i] = c.ID
+    }
+    log.Printf("Verifying %d subreddits, %d posts and %d users against the engine",
+        len(subredditIDs), len(state.model.posts), len(state.UserIDs))
+
+    model, engineClient, userIDs := state.model, state.Client, state.UserIDs
+    self, root := context.Self(), context.ActorSystem().Root
+    go func() {
+        root.Send(self, &verified{Divergences: model.verify(engineClient, userIDs, subredditIDs)})
+    }()
+}
+
+// finish reports, answers the stop request and stops the actor.
+func (state *SimulatorActor) finish(context actor.Context) {
+    state.reportDistribution()
+    state.writeReport()
+    if state.trace != nil {
+        if err := state.trace.Close(); err != nil {
+            log.Printf("Failed to write %s: %v", state.Config.TracePath, err)
+        }
+    }
+    state.reportToCoordinator(context, true)
+    if state.stopper != nil {
+        context.Send(state.stopper, proto.Clone(state.Stats))
+    }
+    context.Stop(context.Self())
+    if state.Config.OnFinish != nil {
+        state.Config.OnFinish()
+    }
+}
+
+// writeReport logs the final metrics and writes the report files.
+func (state *SimulatorActor) writeReport() {
+    elapsed := state.Config.Clock.Now().Sub(state.startedAt)
+    report := &Report{
+        Label:           state.Config.Label,
+        Scenario:        state.Config.Scenario.Name,
+        StartedAt:       state.startedAt,
+        DurationSeconds: elapsed.Seconds(),
+        Users:           len(state.UserIDs),
+        Subreddits:      len(state.Communities),
+        Posts:           state.Stats.TotalPosts,
+        Comments:        state.Stats.TotalComments,
+        Messages:        state.Stats.TotalMessages,
+        Operations:      operationReports(state.metrics, elapsed),
+        TargetRate:      state.Config.Rate,
+        Scheduled:       state.scheduled,
+        Behind:          state.everBehind,
+        Verified:        state.Config.Verify,
+        Divergences:     state.divergences,
+    }
+    if state.Config.Rate > 0 && state.everBehind {
+        log.Printf("The engine fell behind %.0f requests/s during the run", state.Config.Rate)
+    }
+    logOperations(fmt.Sprintf("Final metrics after %s", elapsed.Round(time.Second)), report.Operations)
+
+    if state.Config.ReportJSON != "" {
+        if err := report.WriteJSON(state.Config.ReportJSON); err != nil {
+            log.Printf("Failed to write %s: %v", state.Config.ReportJSON, err)
+        }
+    }
+    if state.Config.ReportCSV != "" {
+        if err := report.WriteCSV(state.Config.ReportCSV); err != nil {
+            log.Printf("Failed to write %s: %v", state.Config.ReportCSV, err)
+        }
+    }
+}
+
+func (state *SimulatorActor) broadcast(context actor.Context, msg interface{}) {
+    for _, pid := range state.Users {
+        context.Send(pid, msg)
+    }
+}
+
+// spawnUsers starts one actor per user; each registers itself.
+func (state *SimulatorActor) spawnUsers(context actor.Context) {
+    log.Println("Beginning user registration phase...")
+    state.phase = registering
+    state.unregistered = state.Config.NumUsers
+    state.UserIDs = make([]string, state.Config.NumUsers)
+
+    for i := 0; i < state.Config.NumUsers; i++ {
+        // The producer hands back the same user after a restart
+        user := newUserActor(state.Config.Partition.global(i), state.Config, state.Client, state.trace)
+        pid := context.Spawn(actor.PropsFromProducer(func() actor.Actor {
+            return user
+        }))
+        state.Users[pid.Id] = pid
+    }
+    state.advance(context)
+}
+
+func (state *SimulatorActor) createSubreddits(context actor.Context) {
+    log.Printf("Creating %d subreddits...", state.Config.NumSubreddits)
+    state.phase = creatingSubreddits
+    state.created = make([]*community, state.Config.NumSubreddits)
+
+    for i := 0; i < state.Config.NumSubreddits; i++ {
+        subredditName := fmt.Sprintf("subreddit_%d", i)
+        state.request(context, &messages.CreateSubRedditMsg{
+            Name:           subredditName,
+            UserId:         state.UserIDs[state.rng.Intn(len(state.UserIDs))],
+            IdempotencyKey: client.NewIdempotencyKey(),
+        }, func(response *messages.OperationResponse, err error) {
+            if err != nil {
+                log.Printf("Failed to create %s: %v", subredditName, err)
+                return
+            }
+            state.created[i] = &community{
+                ID:      response.Id,
+                Name:    subredditName,
+                Members: make(map[string]bool),
+            }
+            state.Stats.ActiveSubreddits++
+        })
+    }
+}
+
+// beginUsers gives each subreddit its Zipf share of members, ranked in the
+// order the subreddits were asked for, and sets the users going.
+func (state *SimulatorActor) beginUsers(context actor.Context) {
+    state.phase = running
+    for _, c := range state.created {
+        if c != nil {
+            state.Communities = append(state.Communities, c)
+        }
+    }
+
+    subreddits := make([]string, len(state.Communities))
+    joined := make(map[string][]int)
+    sizes := zipfSizes(len(state.Communities), len(state.UserIDs), state.Config.ZipfExponent)
+    for i, c := range state.Communities {
+        c.Rank = i + 1
+        subreddits[i] = c.ID
+        for _, u := range state.rng.Perm(len(state.UserIDs))[:sizes[i]] {
+            joined[state.UserIDs[u]] = append(joined[state.UserIDs[u]], i)
+        }
+    }
+
+    state.begunAt = state.Config.Clock.Now()
+    state.step, state.active = state.Config.Scenario.At(0)
+    state.active = min(state.Config.Partition.size(state.active), len(state.UserIDs))
+    behavior := state.Config.Scenario.Phases[state.step].Behavior
+    for i, userID := range state.UserIDs {
+        context.Send(state.userPIDs[userID], &begin{
+            Subreddits: subreddits,
+            Joined:     joined[userID],
+            UserIDs:    state.everyone,
+            Behavior:   behavior,
+            Active:     i < state.active,
+            Started:    state.begunAt,
+        })
+    }
+    log.Printf("Setup complete, %d subreddits", len(state.Communities))
+    state.logPhase()
+
+    state.stopPublishing = sendRepeatedly(state.Config.Clock, context, catalogInterval, catalogInterval, &publish{})
+    if state.Config.Rate > 0 {
+        state.paceStart = state.Config.Clock.Now()
+        state.stopPacing = sendRepeatedly(state.Config.Clock, context, paceInterval, paceInterval, &pace{})
+    }
+}
+
+// sendRepeatedly sends msg to the actor every interval on c, the first
+// time after first, so that a run can be stepped through with a
+// clock.Fake.
+func sendRepeatedly(c clock.Clock, context actor.Context, first, interval time.Duration, msg interface{}) scheduler.CancelFunc {
+    system, self := context.ActorSystem(), context.Self()
+    return clock.Repeat(c, first, interval, func() {
+        system.Root.Send(self, msg)
+    })
+}
+
+func (state *SimulatorActor) stopTimers() {
+    for _, cancel := range []*scheduler.CancelFunc{&state.stopPublishing, &state.stopSummaries, &state.stopPacing} {
+        if *cancel != nil {
+            (*cancel)()
+            *cancel = nil
+        }
+    }
+}
+
+// followScenario moves to the phase the scenario's clock is in and brings
+// the active users up or down to its count.
+func (state *SimulatorActor) followScenario(context actor.Context) {
+    step, target := state.Config.Scenario.At(state.Config.Clock.Now().Sub(state.begunAt))
+    target = min(state.Config.Partition.size(target), len(state.UserIDs))
+
+    if step != state.step {
+        state.step = step
+        state.hot = ""
+        state.broadcast(context, &phaseChange{Behavior: state.Config.Scenario.Phases[step].Behavior})
+        state.logPhase()
+    }
+    // The hot post is the newest one when a focused phase begins
+    if state.hot == "" && state.Config.Scenario.Phases[step].Behavior.Focus > 0 && len(state.recentPosts) > 0 {
+        state.hot = state.recentPosts[len(state.recentPosts)-1].ID
+        log.Printf("Hot post is %s", state.hot)
+    }
+
+    for ; state.active < target; state.active++ {
+        context.Send(state.userPIDs[state.UserIDs[state.active]], &activate{Active: true})
+    }
+    for ; state.active > target; state.active-- {
+        context.Send(state.userPIDs[state.UserIDs[state.active-1]], &activate{Active: false})
+    }
+}
+
+func (state *SimulatorActor) logPhase() {
+    phase := state.Config.Scenario.Phases[state.step]
+    log.Printf("Phase %d/%d %s: %d users active, heading for %d",
+        state.step+1, len(state.Config.Scenario.Phases), phase.Name, state.active, state.Config.Partition.size(phase.Users))
+    if personas := phase.Behavior.Personas; len(personas) > 0 {
+        total := 0.0
+        for _, p := range personas {
+            total += p.Share
+        }
+        mix := make([]string, len(personas))
+        for i, p := range personas {
+            mix[i] = fmt.Sprintf("%s %.0f%%", p.Name, 100*p.Share/total)
+        }
+        log.Printf("Personas: %s", strings.Join(mix, ", "))
+    }
+}
+
+// pace hands out the open-loop actions that have come due, each to a random
+// active user, stamped with when it was due. The timer only sets how often
+// that happens; a late tick hands out everything it missed.
+func (state *SimulatorActor) pace(context actor.Context) {
+    if !state.pausedAt.IsZero() {
+        return
+    }
+    due := uint64(state.Config.Clock.Now().Sub(state.paceStart).Seconds() * state.Config.Rate)
+    for ; state.scheduled < due; state.scheduled++ {
+        if state.active == 0 {
+            state.answered++ // no one to do it
+            continue
+        }
+        offset := time.Duration(float64(state.scheduled) / state.Config.Rate * float64(time.Second))
+        userID := state.UserIDs[state.rng.Intn(state.active)]
+        context.Send(state.userPIDs[userID], &perform{Due: state.paceStart.Add(offset)})
+    }
+}
+
+// checkPace notes when scheduled actions pile up unanswered. Users report
+// once a second, so up to a second's worth outstanding is normal.
+func (state *SimulatorActor) checkPace() {
+    if state.Config.Rate <= 0 {
+        return
+    }
+    outstanding := float64(state.scheduled - min(state.answered, state.scheduled))
+    behind := outstanding > 2*state.Config.Rate
+    switch {
+    case behind && !state.behind:
+        log.Printf("Engine can't keep up with %.0f requests/s: %.0f outstanding", state.Config.Rate, outstanding)
+        state.everBehind = true
+    case !behind && state.behind:
+        log.Printf("Engine caught up with %.0f requests/s", state.Config.Rate)
+    }
+    state.behind = behind
+}
+
+// record adds a user's activity to the stats and the recent content.
+func (state *SimulatorActor) record(a *activity) {
+    switch a.Kind {
+    case joinedSubreddit:
+        state.Communities[a.Subreddit].Members[a.UserID] = true
+    case createdPost:
+        state.Communities[a.Subreddit].Posts++
+        state.recentPosts = appendRecent(state.recentPosts, a.Post)
+        state.Stats.TotalPosts++
+    case createdRepost:
+        state.Communities[a.Subreddit].Reposts++
+        state.recentPosts = appendRecent(state.recentPosts, a.Post)
+        state.Stats.TotalPosts++
+    case createdComment:
+        state.recentComments = appendRecent(state.recentComments, a.Comment)
+        state.Stats.TotalComments++
+    case sentMessage:
+        state.Stats.TotalMessages++
+    }
+    if state.model != nil {
+        state.model.record(a, state.Communities)
+    }
+}
+
+// publishCatalog sends every user the same snapshot. The slices are copies,
+// so users can read them while the simulator keeps appending.
+func (state *SimulatorActor) publishCatalog(context actor.Context) {
+    state.broadcast(context, &catalog{
+        Posts:    append([]catalogPost(nil), state.recentPosts...),
+        Comments: append([]catalogComment(nil), state.recentComments...),
+        Members:  state.memberWeights(),
+        Hot:      state.hot,
+    })
+}
+
+func appendRecent[T any](recent []T, item T) []T {
+    if len(recent) == recentLimit {
+        recent = append(recent[:0], recent[1:]...)
+    }
+    return append(recent, item)
+}
@@ -0,0 +1,126 @@
+// internal/simulator/trace.go
+package simulator
+
+import (
+    "bufio"
+    "encoding/json"
+    "os"
+    "sync"
+    "time"
+    "redditclone/internal/messages"
+    "google.golang.org/protobuf/encoding/protojson"
+    "google.golang.org/protobuf/proto"
+)
+
+// traceRecord is one line of a trace file: a request as it was sent, when,
+// and what came back. ID is the entity a successful create made; replays
+// use it to map the recorded IDs to the ones the new engine hands out.
+type traceRecord struct {
+    At      time.Duration   `json:"at"` // since the run started
+    Type    string          `json:"type"`
+    Message json.RawMessage `json:"message"`
+    ID      string          `json:"id,omitempty"`
+    Error   string          `json:"error,omitempty"`
+}
+
+// Trace writes the requests of a run to a file, one JSON record per line.
+// Users record concurrently, so it is safe for concurrent use. Records are
+// written as responses arrive; replays order them by At.
+type Trace struct {
+    mu      sync.Mutex
+    file    *os.File
+    w       *bufio.Writer
+    started time.Time
+    err     error
+}
+
+func CreateTrace(path string, started time.Time) (*Trace, error) {
+    file, err := os.Create(path)
+    if err != nil {
+        return nil, err
+    }
+    return &Trace{file: file, w: bufio.NewWriter(file), started: started}, nil
+}
+
+func (t *Trace) record(msg proto.Message, sent time.Time, response *messages.OperationResponse, err error) {
+    data, marshalErr := protojson.Marshal(msg)
+    if marshalErr != nil {
+        return
+    }
+
+    record := traceRecord{
+        At:      sent.Sub(t.started),
+        Type:    string(msg.ProtoReflect().Descriptor().FullName()),
+        Message: data,
+    }
+    if err != nil {
+        record.Error = err.Error()
+    } else if isCreate(msg) {
+        record.ID = response.Id
+    }
+
+    line, _ := json.Marshal(record)
+
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    if t.err == nil {
+        _, t.err = t.w.Write(append(line, '\n'))
+    }
+}
+
+// Close flushes the trace and reports the first write error, if any.
+func (t *Trace) Close() error {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    if t.err == nil {
+        t.err = t.w.Flush()
+    }
+    if err := t.file.Close(); t.err == nil {
+        t.err = err
+    }
+    return t.err
+}
+
+func isCreate(msg proto.Message) bool {
+    switch msg.(type) {
+    case *messages.RegisterUserMsg, *messages.CreateSubRedditMsg, *messages.CreatePostMsg,
+        *messages.CreateCommentMsg, *messages.SendDirectMessageMsg:
+        return true
+    }
+    return false
+}
+
+// idFields returns pointers to the fields of msg that hold engine IDs.
+func idFields(msg proto.Message) []*string {
+    switch msg := msg.(type) {
+    case *messages.CreateSubRedditMsg:
+        return []*string{&msg.UserId}
+    case *messages.JoinSubRedditMsg:
+        return []*string{&msg.Subreddit, &msg.UserId}
+    case *messages.LeaveSubRedditMsg:
+        return []*string{&msg.Subreddit, &msg.UserId}
+    case *messages.CreatePostMsg:
+        return []*string{&msg.Subreddit, &msg.AuthorId}
+    case *messages.CreateCommentMsg:
+        return []*string{&msg.PostId, &msg.ParentId, &msg.AuthorId}
+    case *messages.VoteMsg:
+        return []*string{&msg.ItemId, &msg.UserId}
+    case *messages.SendDirectMessageMsg:
+        return []*string{&msg.FromUserId, &msg.ToUserId}
+    case *messages.GetUserMsg:
+        return []*string{&msg.UserId}
+    case *messages.GetSubRedditMsg:
+        return []*string{&msg.Subreddit}
+    case *messages.GetSubscriptionsMsg:
+        return []*string{&msg.UserId}
+    case *messages.GetPostMsg:
+        return []*string{&msg.PostId}
+    case *messages.GetCommentsMsg:
+        return []*string{&msg.PostId}
+    case *messages.GetFeedMsg:
+        return []*string{&msg.UserId}
+    case *messages.GetDirectMessagesMsg:
+        return []*string{&msg.UserId}
+    }
+    return nil
+}
@@ -0,0 +1,302 @@
+// internal/simulator/user.go
+package simulator
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "math/rand"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/scheduler"
+    "redditclone/internal/messages"
+    "redditclone/pkg/client"
+)
+
+// Messages between the simulator and its users. They never leave the
+// process, so they are plain Go types rather than protos.
+
+// registered reports a user's registration; UserID is empty if it failed.
+type registered struct {
+    Index    int
+    Username string
+    UserID   string
+}
+
+// begin starts a registered user.
+type begin struct {
+    Subreddits []string // subreddit IDs, indexed by rank - 1
+    Joined     []int    // subreddits the user starts in
+    UserIDs    []string // everyone, for direct messages
+    Behavior   Behavior
+    Active     bool
+    Started    time.Time // when the scenario's clock started
+}
+
+// phaseChange tells users the scenario moved on to a phase with a new
+// behaviour.
+type phaseChange struct {
+    Behavior Behavior
+}
+
+// activate brings a user into the scenario or takes it out. Inactive users
+// stay registered but don't act.
+type activate struct {
+    Active bool
+}
+
+// catalog is the simulator's periodic snapshot of recent content. Users
+// share it and must not modify it.
+type catalog struct {
+    Posts    []catalogPost
+    Comments []catalogComment
+    Members  []int  // member count per subreddit
+    Hot      string // post the current phase focuses on, if any
+}
+
+type catalogPost struct {
+    ID        string
+    Subreddit int
+    Title     string
+    Content   string
+}
+
+type catalogComment struct {
+    ID     string
+    PostID string
+}
+
+type activityKind int
+
+const (
+    joinedSubreddit activityKind = iota
+    createdPost
+    createdRepost
+    createdComment
+    sentMessage
+    voted      // verified runs only
+    unanswered // verified runs only: ItemID is what the request may have changed
+)
+
+// activity reports something a user created, for the simulator's stats and
+// catalog, and in verified runs its model.
+type activity struct {
+    Kind      activityKind
+    UserID    string
+    Subreddit int
+    Post      catalogPost
+    Comment   catalogComment
+    ItemID    string // the item voted on or the message sent
+    ToUserID  string
+    Upvote    bool
+}
+
+// userDone tells the simulator a stopped user has no requests left.
+type userDone struct{}
+
+// perform asks a user to act now, in an open-loop run. Due is when the
+// schedule wanted the request sent.
+type perform struct {
+    Due time.Time
+}
+
+// metricsDelta hands the simulator what a user recorded since its last
+// delta. The user starts a new metrics map, so the simulator owns this one.
+// Answered and Idle count the performs that got a response and the ones that
+// found nothing to do or were held off after an overload.
+type metricsDelta struct {
+    Metrics  metrics
+    Answered uint64
+    Idle     uint64
+}
+
+// userActor is one simulated user. It acts on its own ticks, one request at
+// a time, as its persona would, and goes offline and back on its own.
+type userActor struct {
+    Index      int
+    Username   string
+    UserID     string
+    Connected  bool
+    Subreddits []string
+    Joined     map[int]bool
+    UserIDs    []string
+    Catalog    *catalog
+    Config     Config
+    Behavior   Behavior
+
+    requests
+    rng         *rand.Rand
+    draw        float64   // where the user falls among the personas
+    started     time.Time // when the scenario's clock started
+    switchAt    time.Time // when the current session or gap ends, with sessions
+    offlineAt   time.Time // when the user last went offline
+    missed      []string  // feed posts from while it was offline, to see to first
+    unanswered  []string  // users whose messages came while it was offline
+    running     bool
+    active      bool
+    paused      bool
+    stopping    bool
+    stopTicks   scheduler.CancelFunc
+    idle        uint64
+}
+
+// newUserActor returns user number index. Its choices come from its own
+// generator, seeded from the run's seed and the index. Its place among the
+// personas steps by the golden ratio, so however many users are active, the
+// mix is close to the personas' shares.
+func newUserActor(index int, config Config, engineClient *client.Client, trace *Trace) *userActor {
+    rng := rand.New(rand.NewSource(config.Seed + int64(index) + 1))
+    return &userActor{
+        Index:     index,
+        Username:  fmt.Sprintf("user_%d", index),
+        Connected: true,
+        Joined:    make(map[int]bool),
+        Catalog:   &catalog{},
+        Config:    config,
+        requests:  requests{client: engineClient, metrics: metrics{}, trace: trace, clock: config.Clock, rng: rng},
+        rng:       rng,
+        draw:      math.Mod(float64(index)*math.Phi, 1),
+    }
+}
+
+func (u *userActor) Receive(context actor.Context) {
+    switch msg := context.Message().(type) {
+    case *actor.Started:
+        // After a restart the session carries on where it was
+        if u.UserID == "" {
+            u.register(context)
+        } else {
+            u.resume(context)
+        }
+
+    case *begin:
+        u.Subreddits = msg.Subreddits
+        u.UserIDs = msg.UserIDs
+        for _, subreddit := range msg.Joined {
+            u.join(context, subreddit)
+        }
+        u.Behavior = msg.Behavior
+        u.started = msg.Started
+        u.active = msg.Active
+        u.running = true
+        u.resume(context)
+
+    case *phaseChange:
+        u.Behavior = msg.Behavior
+
+    case *activate:
+        u.active = msg.Active
+        if u.active {
+            u.resume(context)
+        } else {
+            u.stopTicking()
+        }
+
+    case *catalog:
+        u.Catalog = msg
+        u.flushMetrics(context)
+
+    case *tick:
+        u.act(context)
+
+    case *perform:
+        u.due = msg.Due
+        if !u.running || !u.active || u.paused || u.stopping || u.backingOff() || !u.perform(context, 3) {
+            u.idle++
+        }
+        u.due = time.Time{}
+
+    case *messages.PauseSimulation:
+        u.paused = true
+        u.stopTicking()
+
+    case *messages.ResumeSimulation:
+        u.paused = false
+        u.resume(context)
+
+    case *messages.StopSimulation:
+        u.stopping = true
+        u.stopTicking()
+        u.finishIfIdle(context)
+
+    case *actor.Restarting, *actor.Stopping:
+        u.stopTicking()
+    }
+}
+
+// request is requests.send that also finishes a stopping user once its
+// last request is back. In verified runs it tells the simulator what a
+// request that got no answer, or an internal error, may have changed: the
+// engine may have got part way.
+func (u *userActor) request(context actor.Context, msg interface{}, then func(*messages.OperationResponse, error)) {
+    u.send(context, msg, func(response *messages.OperationResponse, err error) {
+        if u.Config.Verify && err != nil && (response == nil || errors.Is(err, client.ErrInternal)) {
+            for _, key := range unsureKeys(msg) {
+                u.report(context, &activity{Kind: unanswered, ItemID: key})
+            }
+        }
+        then(response, err)
+        u.finishIfIdle(context)
+    })
+}
+
+func (u *userActor) finishIfIdle(context actor.Context) {
+    if u.stopping && u.inFlight == 0 {
+        u.flushMetrics(context)
+        context.Request(context.Parent(), &userDone{})
+        context.Stop(context.Self())
+    }
+}
+
+// resume starts ticking if the user has begun, is active, and is neither
+// paused nor stopping. Open-loop users don't tick; the simulator tells them
+// when to act.
+func (u *userActor) resume(context actor.Context) {
+    if u.running && u.active && !u.paused && !u.stopping && u.Config.Rate == 0 {
+        u.startTicking(context)
+    }
+}
+
+// startTicking schedules the user's turns, starting at a random point in
+// the first interval so users don't all act at once.
+func (u *userActor) startTicking(context actor.Context) {
+    u.stopTicking()
+    interval := u.Config.TickInterval
+    u.stopTicks = sendRepeatedly(u.Config.Clock, context, time.Duration(u.rng.Int63n(int64(interval))+1), interval, &tick{})
+}
+
+func (u *userActor) stopTicking() {
+    if u.stopTicks != nil {
+        u.stopTicks()
+        u.stopTicks = nil
+    }
+}
+
+func (u *userActor) register(context actor.Context) {
+    u.request(context, &messages.RegisterUserMsg{
+        Username:       u.Username,
+        IdempotencyKey: client.NewIdempotencyKey(),
+    }, func(response *messages.OperationResponse, err error) {
+        if err == nil {
+            u.UserID = response.Id
+        }
+        context.Request(context.Parent(), &registered{
+            Index:    u.Index,
+            Username: u.Username,
+            UserID:   u.UserID,
+        })
+    })
+}
+
+func (u *userActor) flushMetrics(context actor.Context) {
+    if len(u.metrics) > 0 || u.idle > 0 {
+        context.Send(context.Parent(), &metricsDelta{Metrics: u.metrics, Answered: u.answered, Idle: u.idle})
+        u.metrics = metrics{}
+        u.answered, u.idle = 0, 0
+    }
+}
+
+func (u *userActor) report(context actor.Context, a *activity) {
+    a.UserID = u.UserID
+    context.Send(context.Parent(), a)
+}
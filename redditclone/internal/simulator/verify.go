@@ -0,0 +1,301 @@
+// internal/simulator/verify.go
+package simulator
+
+import (
+    "fmt"
+    "log"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+    "redditclone/internal/messages"
+    "redditclone/pkg/client"
+)
+
+// verifyConcurrency bounds the reads in flight while verifying.
+const verifyConcurrency = 16
+
+// model is what the engine should hold once a verified run is over, built
+// from the answers users got. A request that went unanswered may or may not
+// have been applied, so whatever it could have changed is unsure and left
+// unchecked.
+type model struct {
+    members  map[string]map[string]bool // subreddit ID to members
+    authors  map[string]string          // post or comment ID to author
+    posts    []string
+    comments map[string]map[string]bool // post ID to its comments
+    votes    map[string]map[string]bool // item ID to voters, true for upvotes
+    inboxes  map[string]map[string]bool // user ID to messages received
+    unsure   map[string]bool            // subreddits, items, posts, inboxes and karma
+}
+
+// verified hands the simulator what verification found.
+type verified struct {
+    Divergences []string
+}
+
+func newModel() *model {
+    return &model{
+        members:  make(map[string]map[string]bool),
+        authors:  make(map[string]string),
+        comments: make(map[string]map[string]bool),
+        votes:    make(map[string]map[string]bool),
+        inboxes:  make(map[string]map[string]bool),
+        unsure:   make(map[string]bool),
+    }
+}
+
+// unsureKeys is what msg would have changed, for requests that go
+// unanswered: a subreddit's members, an item's votes, a post's comments, a
+// user's inbox or an author's karma. A post or comment nobody heard was
+// created may still be voted on, so its author's karma can't be told.
+func unsureKeys(msg interface{}) []string {
+    switch msg := msg.(type) {
+    case *messages.JoinSubRedditMsg:
+        return []string{msg.Subreddit}
+    case *messages.VoteMsg:
+        return []string{msg.ItemId}
+    case *messages.CreatePostMsg:
+        return []string{karmaKey(msg.AuthorId)}
+    case *messages.CreateCommentMsg:
+        return []string{msg.PostId, karmaKey(msg.AuthorId)}
+    case *messages.SendDirectMessageMsg:
+        return []string{msg.ToUserId}
+    }
+    return nil
+}
+
+func karmaKey(userID string) string {
+    return "karma:" + userID
+}
+
+func (m *model) record(a *activity, communities []*community) {
+    switch a.Kind {
+    case joinedSubreddit:
+        set(m.members, communities[a.Subreddit].ID)[a.UserID] = true
+    case createdPost, createdRepost:
+        m.authors[a.Post.ID] = a.UserID
+        m.posts = append(m.posts, a.Post.ID)
+    case createdComment:
+        m.authors[a.Comment.ID] = a.UserID
+        set(m.comments, a.Comment.PostID)[a.Comment.ID] = true
+    case voted:
+        set(m.votes, a.ItemID)[a.UserID] = a.Upvote
+    case sentMessage:
+        set(m.inboxes, a.ToUserID)[a.ItemID] = true
+    case unanswered:
+        m.unsure[a.ItemID] = true
+    }
+}
+
+func set(sets map[string]map[string]bool, key string) map[string]bool {
+    if sets[key] == nil {
+        sets[key] = make(map[string]bool)
+    }
+    return sets[key]
+}
+
+// verify reads back every subreddit, post and user the run touched and
+// returns each way the engine differs from the model, sorted. A read that
+// fails is reported too, since it leaves something unchecked.
+func (m *model) verify(engineClient *client.Client, userIDs, subredditIDs []string) []string {
+    // Karma is the score of everything a user wrote, so it is only
+    // certain if all of their scores are
+    karma := make(map[string]int32)
+    unsureKarma := make(map[string]bool)
+    for itemID, author := range m.authors {
+        for _, up := range m.votes[itemID] {
+            if up {
+                karma[author]++
+            } else {
+                karma[author]--
+            }
+        }
+        unsureKarma[author] = unsureKarma[author] || m.unsure[itemID]
+    }
+
+    for _, userID := range userIDs {
+        unsureKarma[userID] = unsureKarma[userID] || m.unsure[karmaKey(userID)]
+    }
+
+    var checks []func() []string
+    for _, subredditID := range subredditIDs {
+        checks = append(checks, func() []string { return m.checkSubreddit(engineClient, subredditID) })
+    }
+    for _, postID := range m.posts {
+        checks = append(checks, func() []string { return m.checkPost(engineClient, postID) })
+    }
+    for _, userID := range userIDs {
+        checks = append(checks, func() []string {
+            return m.checkUser(engineClient, userID, karma[userID], unsureKarma[userID])
+        })
+    }
+
+    var (
+        mu          sync.Mutex
+        wg          sync.WaitGroup
+        divergences []string
+        slots       = make(chan struct{}, verifyConcurrency)
+    )
+    for _, check := range checks {
+        wg.Add(1)
+        slots <- struct{}{}
+        go func() {
+            defer wg.Done()
+            found := check()
+            <-slots
+            mu.Lock()
+            divergences = append(divergences, found...)
+            mu.Unlock()
+        }()
+    }
+    wg.Wait()
+    sort.Strings(divergences)
+    return divergences
+}
+
+// read asks the engine for what it holds, waiting out any overload.
+func read(engineClient *client.Client, msg interface{}) (*messages.OperationResponse, error) {
+    backoff := minOverloadBackoff
+    for {
+        response, err := engineClient.Result(engineClient.Future(msg).Result())
+        if !overloaded(err) {
+            return response, err
+        }
+        time.Sleep(backoff)
+        backoff = min(2*backoff, maxOverloadBackoff)
+    }
+}
+
+func (m *model) checkSubreddit(engineClient *client.Client, subredditID string) []string {
+    if m.unsure[subredditID] {
+        return nil
+    }
+    response, err := read(engineClient, &messages.GetSubRedditMsg{Subreddit: subredditID})
+    if err != nil {
+        return []string{fmt.Sprintf("%s: could not read: %v", subredditID, err)}
+    }
+    return compareSets(subredditID, "member", m.members[subredditID], response.GetSubreddit().GetMembers())
+}
+
+// checkPost compares a post's score, and the set and scores of its
+// comments.
+func (m *model) checkPost(engineClient *client.Client, postID string) []string {
+    response, err := read(engineClient, &messages.GetPostMsg{PostId: postID})
+    if err != nil {
+        return []string{fmt.Sprintf("%s: could not read: %v", postID, err)}
+    }
+    post := response.GetPost()
+    divergences := m.compareScore(postID, post.GetUpvotes(), post.GetDownvotes())
+
+    response, err = read(engineClient, &messages.GetCommentsMsg{PostId: postID})
+    if err != nil {
+        return append(divergences, fmt.Sprintf("%s: could not read comments: %v", postID, err))
+    }
+    found := make(map[string]bool)
+    var walk func([]*messages.Comment)
+    walk = func(comments []*messages.Comment) {
+        for _, comment := range comments {
+            found[comment.Id] = true
+            if m.authors[comment.Id] != "" {
+                divergences = append(divergences, m.compareScore(comment.Id, comment.Upvotes, comment.Downvotes)...)
+            }
+            walk(comment.Children)
+        }
+    }
+    walk(response.Comments)
+    if !m.unsure[postID] {
+        divergences = append(divergences, compareSets(postID, "comment", m.comments[postID], found)...)
+    }
+    return divergences
+}
+
+func (m *model) compareScore(itemID string, upvotes, downvotes int32) []string {
+    if m.unsure[itemID] {
+        return nil
+    }
+    var up, down int32
+    for _, isUp := range m.votes[itemID] {
+        if isUp {
+            up++
+        } else {
+            down++
+        }
+    }
+    if up == upvotes && down == downvotes {
+        return nil
+    }
+    return []string{fmt.Sprintf("%s: score is +%d/-%d, expected +%d/-%d", itemID, upvotes, downvotes, up, down)}
+}
+
+// checkUser compares a user's karma and the messages in their inbox.
+func (m *model) checkUser(engineClient *client.Client, userID string, karma int32, unsureKarma bool) []string {
+    var divergences []string
+    if !unsureKarma {
+        response, err := read(engineClient, &messages.GetUserMsg{UserId: userID})
+        if err != nil {
+            return []string{fmt.Sprintf("%s: could not read: %v", userID, err)}
+        }
+        if got := response.GetUser().GetKarma(); got != karma {
+            divergences = append(divergences, fmt.Sprintf("%s: karma is %d, expected %d", userID, got, karma))
+        }
+    }
+
+    if m.unsure[userID] {
+        return divergences
+    }
+    response, err := read(engineClient, &messages.GetDirectMessagesMsg{UserId: userID})
+    if err != nil {
+        return append(divergences, fmt.Sprintf("%s: could not read messages: %v", userID, err))
+    }
+    inbox := make(map[string]bool)
+    for _, message := range response.Messages {
+        if message.ToUserId == userID {
+            inbox[message.Id] = true
+        }
+    }
+    return append(divergences, compareSets(userID, "message", m.inboxes[userID], inbox)...)
+}
+
+// compareSets reports what is missing from got and what it has that it
+// shouldn't.
+func compareSets(owner, what string, expected, got map[string]bool) []string {
+    var missing, extra []string
+    for key := range expected {
+        if !got[key] {
+            missing = append(missing, key)
+        }
+    }
+    for key := range got {
+        if !expected[key] {
+            extra = append(extra, key)
+        }
+    }
+    var divergences []string
+    if len(missing) > 0 {
+        sort.Strings(missing)
+        divergences = append(divergences, fmt.Sprintf("%s: missing %s %s", owner, what, strings.Join(missing, ", ")))
+    }
+    if len(extra) > 0 {
+        sort.Strings(extra)
+        divergences = append(divergences, fmt.Sprintf("%s: unexpected %s %s", owner, what, strings.Join(extra, ", ")))
+    }
+    return divergences
+}
+
+// logDivergences logs what verification found, the first few in full.
+func logDivergences(divergences []string) {
+    const shown = 50
+    if len(divergences) == 0 {
+        log.Printf("Verification passed: the engine agrees with the simulator's model")
+        return
+    }
+    log.Printf("Verification found %d divergences from the simulator's model", len(divergences))
+    for i, divergence := range divergences {
+        if i == shown {
+            log.Printf("  ... and %d more", len(divergences)-shown)
+            break
+        }
+        log.Printf("  %s", divergence)
+    }
+}
@@ -0,0 +1,126 @@
+// internal/simulator/worker.go
+package simulator
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/scheduler"
+    "redditclone/internal/messages"
+    "google.golang.org/protobuf/proto"
+)
+
+// Partition is a worker's share of the population in a distributed run:
+// users Index, Index+Count, Index+2*Count and so on. Striping rather than
+// splitting into ranges keeps every worker equally busy while a scenario
+// ramps up. A Count below two is the whole population.
+type Partition struct {
+    Index int
+    Count int
+}
+
+// size is how many of users 0 to n-1 belong to the partition.
+func (p Partition) size(n int) int {
+    if p.Count < 2 {
+        return n
+    }
+    if n <= p.Index {
+        return 0
+    }
+    return (n - p.Index + p.Count - 1) / p.Count
+}
+
+// global and local convert between the partition's own user numbers and
+// those of the whole population.
+func (p Partition) global(i int) int {
+    if p.Count < 2 {
+        return i
+    }
+    return p.Index + i*p.Count
+}
+
+func (p Partition) local(i int) int {
+    if p.Count < 2 {
+        return i
+    }
+    return (i - p.Index) / p.Count
+}
+
+// WorkerConfig turns a coordinator's assignment into the config of a
+// worker's simulator. Settings local to the worker, like its report files,
+// are left to the caller.
+func WorkerConfig(assignment *messages.WorkerAssignment, coordinator *actor.PID) (Config, error) {
+    var scenario Scenario
+    if err := json.Unmarshal(assignment.Scenario, &scenario); err != nil {
+        return Config{}, fmt.Errorf("scenario from the coordinator: %w", err)
+    }
+
+    partition := Partition{Index: int(assignment.Worker), Count: int(assignment.Workers)}
+    return Config{
+        Scenario:     &scenario,
+        ZipfExponent: assignment.ZipfExponent,
+        TickInterval: time.Duration(assignment.TickInterval),
+        Rate:         assignment.Rate / float64(max(partition.Count, 1)),
+        Seed:         assignment.Seed,
+        Coordinator:  coordinator,
+        Partition:    partition,
+    }, nil
+}
+
+// start tells a worker it is time to begin.
+type start struct{}
+
+// ready tells the coordinator this worker's users have registered.
+func (state *SimulatorActor) ready(context actor.Context) {
+    state.phase = awaitingBegin
+    context.Request(state.Config.Coordinator, &messages.WorkerReady{
+        Worker:  int32(state.Config.Partition.Index),
+        UserIds: state.UserIDs,
+    })
+}
+
+// beginWork takes the subreddits the coordinator created and everyone's
+// user IDs, and begins at the agreed time.
+func (state *SimulatorActor) beginWork(context actor.Context, msg *messages.BeginWork) {
+    if state.phase != awaitingBegin {
+        return
+    }
+
+    state.everyone = msg.UserIds
+    state.created = make([]*community, len(msg.SubredditIds))
+    for i, subredditID := range msg.SubredditIds {
+        state.created[i] = &community{
+            ID:      subredditID,
+            Name:    fmt.Sprintf("subreddit_%d", i),
+            Members: make(map[string]bool),
+        }
+    }
+    state.Stats.ActiveSubreddits = int32(len(msg.SubredditIds))
+
+    if len(state.UserIDs) == 0 {
+        log.Printf("No users registered, nothing to do")
+        state.phase = stopped
+        return
+    }
+    delay := time.Until(time.Unix(0, msg.StartAt))
+    log.Printf("Beginning in %s", delay.Round(time.Millisecond))
+    scheduler.NewTimerScheduler(context.ActorSystem().Root).SendOnce(max(delay, 0), context.Self(), &start{})
+}
+
+// reportToCoordinator sends the worker's running totals, which replace the
+// ones it sent before. The final ones tell the coordinator it is done.
+func (state *SimulatorActor) reportToCoordinator(context actor.Context, final bool) {
+    if state.Config.Coordinator == nil {
+        return
+    }
+    context.Send(state.Config.Coordinator, &messages.WorkerStats{
+        Worker:     int32(state.Config.Partition.Index),
+        Operations: state.metrics.toProto(),
+        Stats:      proto.Clone(state.Stats).(*messages.SimulationStats),
+        Scheduled:  state.scheduled,
+        Behind:     state.everBehind,
+        Final:      final,
+    })
+}
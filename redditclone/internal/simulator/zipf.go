@@ -0,0 +1,83 @@
+// internal/simulator/zipf.go
+package simulator
+
+import (
+    "log"
+    "math"
+    "math/rand"
+)
+
+// community is the simulator's view of one subreddit. Rank 1 is the most
+// popular.
+type community struct {
+    ID      string
+    Name    string
+    Rank    int
+    Members map[string]bool
+    Posts   int
+    Reposts int
+}
+
+// zipfSizes returns the starting member count of n subreddits: the one at
+// rank k gets numUsers/k^s members, and at least one. s = 0 puts every user
+// in every subreddit.
+func zipfSizes(n, numUsers int, s float64) []int {
+    sizes := make([]int, n)
+    for k := range sizes {
+        size := int(math.Round(float64(numUsers) / math.Pow(float64(k+1), s)))
+        sizes[k] = min(max(size, 1), numUsers)
+    }
+    return sizes
+}
+
+// weightedIndex picks an index with probability proportional to its weight,
+// or returns -1 if all weights are zero.
+func weightedIndex(rng *rand.Rand, weights []int) int {
+    total := 0
+    for _, weight := range weights {
+        total += weight
+    }
+    if total == 0 {
+        return -1
+    }
+
+    target := rng.Intn(total)
+    for i, weight := range weights {
+        if target < weight {
+            return i
+        }
+        target -= weight
+    }
+    return -1
+}
+
+// memberWeights returns each community's current member count.
+func (state *SimulatorActor) memberWeights() []int {
+    weights := make([]int, len(state.Communities))
+    for i, c := range state.Communities {
+        weights[i] = len(c.Members)
+    }
+    return weights
+}
+
+// reportDistribution logs members and activity per subreddit next to what
+// the Zipf exponent predicts, so skew can be checked after a run.
+func (state *SimulatorActor) reportDistribution() {
+    expected := zipfSizes(len(state.Communities), len(state.UserIDs), state.Config.ZipfExponent)
+
+    totalPosts := 0
+    for _, c := range state.Communities {
+        totalPosts += c.Posts + c.Reposts
+    }
+
+    log.Printf("Subreddit distribution (zipf s=%.2f)", state.Config.ZipfExponent)
+    log.Printf("%4s  %-16s %8s %8s %6s %8s %7s", "rank", "subreddit", "members", "expected", "posts", "reposts", "share")
+    for i, c := range state.Communities {
+        share := 0.0
+        if totalPosts > 0 {
+            share = 100 * float64(c.Posts+c.Reposts) / float64(totalPosts)
+        }
+        log.Printf("%4d  %-16s %8d %8d %6d %8d %6.1f%%",
+            c.Rank, c.Name, len(c.Members), expected[i], c.Posts, c.Reposts, share)
+    }
+}
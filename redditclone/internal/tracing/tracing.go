@@ -0,0 +1,142 @@
+// internal/tracing/tracing.go
+package tracing
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "time"
+    "github.com/asynkron/protoactor-go/actor"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// propagator carries a span's context between processes as W3C Trace
+// Context, in the traceparent and tracestate message headers.
+var propagator = propagation.TraceContext{}
+
+// File exports the spans of one process to a file, one JSON span per line,
+// as the OpenTelemetry stdout exporter writes them. Processes can share a
+// file: each appends whole lines, so a request's spans from the client and
+// every engine it passed through end up together, to be picked out by
+// their TraceID.
+type File struct {
+    provider *sdktrace.TracerProvider
+    file     *os.File
+}
+
+// OpenFile starts exporting spans to the file at path, appending to it,
+// with service and instance naming the process they come from, e.g.
+// engine and its address.
+func OpenFile(path, service, instance string) (*File, error) {
+    file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+    if err != nil {
+        return nil, err
+    }
+    exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+    if err != nil {
+        file.Close()
+        return nil, err
+    }
+    provider := sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(time.Second)),
+        sdktrace.WithResource(resource.NewSchemaless(
+            semconv.ServiceName(service),
+            semconv.ServiceInstanceID(instance))),
+    )
+    return &File{provider: provider, file: file}, nil
+}
+
+// Tracer is the tracer for spans of the named instrumentation, such as a
+// package.
+func (f *File) Tracer(name string) trace.Tracer {
+    return f.provider.Tracer(name)
+}
+
+// Close writes out the spans not yet exported and closes the file.
+func (f *File) Close() error {
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    if err := f.provider.Shutdown(ctx); err != nil {
+        f.file.Close()
+        return fmt.Errorf("exporting the last spans: %w", err)
+    }
+    return f.file.Close()
+}
+
+// Inject puts the context of the span in ctx, if there is one, into
+// envelope's headers, which protoactor carries to the receiver, across
+// remoting too.
+func Inject(ctx context.Context, envelope *actor.MessageEnvelope) {
+    propagator.Inject(ctx, headerCarrier{envelope: envelope})
+}
+
+// Extract is a context holding the remote span whose context header
+// carries, or context.Background() if it carries none.
+func Extract(header actor.ReadonlyMessageHeader) context.Context {
+    if header == nil {
+        return context.Background()
+    }
+    return propagator.Extract(context.Background(), readonlyCarrier{header: header})
+}
+
+// Sender is a root context whose requests carry the context of the span in
+// ctx, for sending from outside an actor on a span's behalf.
+func Sender(system *actor.ActorSystem, ctx context.Context) *actor.RootContext {
+    if !trace.SpanContextFromContext(ctx).IsValid() {
+        return system.Root
+    }
+    return actor.NewRootContext(system, nil, func(next actor.SenderFunc) actor.SenderFunc {
+        return func(c actor.SenderContext, target *actor.PID, envelope *actor.MessageEnvelope) {
+            Inject(ctx, envelope)
+            next(c, target, envelope)
+        }
+    })
+}
+
+// End ends span, marking it failed if err is set.
+func End(span trace.Span, err error) {
+    if err != nil {
+        span.RecordError(err)
+        span.SetStatus(codes.Error, err.Error())
+    }
+    span.End()
+}
+
+type headerCarrier struct {
+    envelope *actor.MessageEnvelope
+}
+
+func (c headerCarrier) Get(key string) string {
+    return c.envelope.GetHeader(key)
+}
+
+func (c headerCarrier) Set(key, value string) {
+    c.envelope.SetHeader(key, value)
+}
+
+func (c headerCarrier) Keys() []string {
+    if c.envelope.Header == nil {
+        return nil
+    }
+    return c.envelope.Header.Keys()
+}
+
+type readonlyCarrier struct {
+    header actor.ReadonlyMessageHeader
+}
+
+func (c readonlyCarrier) Get(key string) string {
+    return c.header.Get(key)
+}
+
+func (c readonlyCarrier) Set(string, string) {}
+
+func (c readonlyCarrier) Keys() []string {
+    return c.header.Keys()
+}
@@ -0,0 +1,499 @@
+// pkg/client/client.go
+package client
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "time"
+
+    "github.com/asynkron/protoactor-go/actor"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/trace"
+    "google.golang.org/protobuf/proto"
+    "redditclone/internal/actors"
+    "redditclone/internal/messages"
+    "redditclone/internal/tracing"
+    "redditclone/pkg/engine"
+)
+
+// Config controls timeouts, retries and health checks. Start from
+// DefaultConfig and change what you need.
+type Config struct {
+    // Timeout bounds each attempt of a call.
+    Timeout time.Duration
+
+    // Retries is how many more times a call is tried after a timeout, an
+    // unreachable engine, a rate limit or an overloaded manager. Creates
+    // are retried under an idempotency key, so a retry never creates a
+    // second entity.
+    Retries int
+
+    // Backoff is the wait before the first retry; it doubles after each
+    // attempt up to MaxBackoff.
+    Backoff    time.Duration
+    MaxBackoff time.Duration
+
+    // HealthInterval is how often the engine is pinged in the background.
+    // Zero turns the pings off; calls still update the health state.
+    HealthInterval time.Duration
+
+    // UnhealthyAfter is how many failed attempts in a row mark the engine
+    // unhealthy.
+    UnhealthyAfter int
+
+    // OnHealthChange, if set, is called whenever Healthy changes.
+    OnHealthChange func(healthy bool)
+
+    // Tracer, if set, traces each attempt of a call as a client span. The
+    // span's context travels in the request's headers, so the engine's
+    // spans for the request join its trace.
+    Tracer trace.Tracer
+}
+
+func DefaultConfig() Config {
+    return Config{
+        Timeout:        5 * time.Second,
+        Retries:        3,
+        Backoff:        100 * time.Millisecond,
+        MaxBackoff:     2 * time.Second,
+        HealthInterval: 5 * time.Second,
+        UnhealthyAfter: 3,
+    }
+}
+
+// Client is a typed front end to the engine's manager actors. It is safe for
+// concurrent use.
+type Client struct {
+    root     *actor.RootContext
+    managers actors.Managers
+    config   Config
+
+    failures atomic.Int32
+    healthy  atomic.Bool
+
+    done      chan struct{}
+    closeOnce sync.Once
+}
+
+var _ engine.Engine = (*Client)(nil)
+
+// New returns a client for the engine listening at engineAddress. root must
+// belong to an actor system with remoting started.
+func New(root *actor.RootContext, engineAddress string, config Config) *Client {
+    return NewWithManagers(root, &actors.Managers{
+        UserManager:      actor.NewPID(engineAddress, actors.UserManagerName),
+        SubredditManager: actor.NewPID(engineAddress, actors.SubredditManagerName),
+        PostManager:      actor.NewPID(engineAddress, actors.PostManagerName),
+        CommentManager:   actor.NewPID(engineAddress, actors.CommentManagerName),
+        MessageManager:   actor.NewPID(engineAddress, actors.MessageManagerName),
+    }, config)
+}
+
+// NewWithManagers returns a client for managers that are already known, such
+// as ones spawned in the same process.
+func NewWithManagers(root *actor.RootContext, managers *actors.Managers, config Config) *Client {
+    c := &Client{
+        root:     root,
+        managers: *managers,
+        config:   config,
+        done:     make(chan struct{}),
+    }
+    c.healthy.Store(true)
+
+    if config.HealthInterval > 0 {
+        go c.monitor()
+    }
+    return c
+}
+
+// Close stops the background health checks. It leaves the actor system
+// running.
+func (c *Client) Close() {
+    c.closeOnce.Do(func() {
+        close(c.done)
+    })
+}
+
+// Healthy reports whether the engine answered recently.
+func (c *Client) Healthy() bool {
+    return c.healthy.Load()
+}
+
+// Ping checks that the engine's user manager answers.
+func (c *Client) Ping() error {
+    _, err := c.request(c.managers.UserManager, &messages.PingMsg{})
+    return err
+}
+
+func (c *Client) monitor() {
+    ticker := time.NewTicker(c.config.HealthInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-c.done:
+            return
+        case <-ticker.C:
+            c.Ping()
+        }
+    }
+}
+
+func (c *Client) RegisterUser(username string) (string, error) {
+    response, err := c.retry(c.managers.UserManager, &messages.RegisterUserMsg{
+        Username:       username,
+        IdempotencyKey: NewIdempotencyKey(),
+    })
+    if err != nil {
+        return "", err
+    }
+    return response.Id, nil
+}
+
+func (c *Client) GetUser(userID string) (engine.User, error) {
+    response, err := c.retry(c.managers.UserManager, &messages.GetUserMsg{
+        UserId: userID,
+    })
+    if err != nil {
+        return engine.User{}, err
+    }
+
+    user := response.GetUser()
+    return engine.User{
+        ID:       user.Id,
+        Username: user.Username,
+        Karma:    int(user.Karma),
+    }, nil
+}
+
+// SaveItem saves a post or comment to the user's profile, or unsaves it.
+func (c *Client) SaveItem(userID, itemID string, saved bool) error {
+    _, err := c.retry(c.managers.UserManager, &messages.SaveItemMsg{
+        UserId: userID,
+        ItemId: itemID,
+        Saved:  saved,
+    })
+    return err
+}
+
+// SetPreference sets one of the user's preferences; an empty value clears
+// it.
+func (c *Client) SetPreference(userID, key, value string) error {
+    _, err := c.retry(c.managers.UserManager, &messages.SetPreferenceMsg{
+        UserId: userID,
+        Key:    key,
+        Value:  value,
+    })
+    return err
+}
+
+func (c *Client) CreateSubreddit(creatorID, name, description string) (string, error) {
+    response, err := c.retry(c.managers.SubredditManager, &messages.CreateSubRedditMsg{
+        Name:           name,
+        UserId:         creatorID,
+        Description:    description,
+        IdempotencyKey: NewIdempotencyKey(),
+    })
+    if err != nil {
+        return "", err
+    }
+    return response.Id, nil
+}
+
+func (c *Client) JoinSubreddit(userID, subredditID string) error {
+    _, err := c.retry(c.managers.SubredditManager, &messages.JoinSubRedditMsg{
+        Subreddit: subredditID,
+        UserId:    userID,
+    })
+    return err
+}
+
+func (c *Client) LeaveSubreddit(userID, subredditID string) error {
+    _, err := c.retry(c.managers.SubredditManager, &messages.LeaveSubRedditMsg{
+        Subreddit: subredditID,
+        UserId:    userID,
+    })
+    return err
+}
+
+func (c *Client) CreatePost(authorID, subredditID, title, content string) (string, error) {
+    response, err := c.retry(c.managers.PostManager, &messages.CreatePostMsg{
+        Title:          title,
+        Content:        content,
+        Subreddit:      subredditID,
+        AuthorId:       authorID,
+        IdempotencyKey: NewIdempotencyKey(),
+    })
+    if err != nil {
+        return "", err
+    }
+    return response.Id, nil
+}
+
+func (c *Client) GetPost(postID string) (engine.Post, error) {
+    response, err := c.retry(c.managers.PostManager, &messages.GetPostMsg{
+        PostId: postID,
+    })
+    if err != nil {
+        return engine.Post{}, err
+    }
+    return toPost(response.GetPost()), nil
+}
+
+func (c *Client) CreateComment(authorID, postID, parentID, content string) (string, error) {
+    response, err := c.retry(c.managers.CommentManager, &messages.CreateCommentMsg{
+        Content:        content,
+        PostId:         postID,
+        ParentId:       parentID,
+        AuthorId:       authorID,
+        IdempotencyKey: NewIdempotencyKey(),
+    })
+    if err != nil {
+        return "", err
+    }
+    return response.Id, nil
+}
+
+func (c *Client) GetComments(postID string) ([]engine.Comment, error) {
+    response, err := c.retry(c.managers.CommentManager, &messages.GetCommentsMsg{
+        PostId: postID,
+    })
+    if err != nil {
+        return nil, err
+    }
+    return toComments(response.Comments), nil
+}
+
+// Vote is retried like the other calls: voting the same way twice is a
+// no-op.
+func (c *Client) Vote(userID, itemID string, upvote bool) error {
+    _, err := c.retry(c.voteManager(itemID), &messages.VoteMsg{
+        ItemId:   itemID,
+        UserId:   userID,
+        IsUpvote: upvote,
+    })
+    return err
+}
+
+func (c *Client) SendDirectMessage(fromID, toID, content string) (string, error) {
+    response, err := c.retry(c.managers.MessageManager, &messages.SendDirectMessageMsg{
+        FromUserId:     fromID,
+        ToUserId:       toID,
+        Content:        content,
+        IdempotencyKey: NewIdempotencyKey(),
+    })
+    if err != nil {
+        return "", err
+    }
+    return response.Id, nil
+}
+
+func (c *Client) GetDirectMessages(userID string) ([]engine.DirectMessage, error) {
+    response, err := c.retry(c.managers.MessageManager, &messages.GetDirectMessagesMsg{
+        UserId: userID,
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    inbox := make([]engine.DirectMessage, 0, len(response.Messages))
+    for _, message := range response.Messages {
+        inbox = append(inbox, engine.DirectMessage{
+            ID:        message.Id,
+            FromID:    message.FromUserId,
+            ToID:      message.ToUserId,
+            Content:   message.Content,
+            CreatedAt: message.Timestamp.AsTime(),
+        })
+    }
+    return inbox, nil
+}
+
+func (c *Client) GetFeed(userID string) ([]engine.Post, error) {
+    response, err := c.retry(c.managers.PostManager, &messages.GetFeedMsg{
+        UserId: userID,
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    feed := make([]engine.Post, 0, len(response.Posts))
+    for _, post := range response.Posts {
+        feed = append(feed, toPost(post))
+    }
+    return feed, nil
+}
+
+// request sends msg once and turns a failed response into an error.
+func (c *Client) request(pid *actor.PID, msg interface{}) (*messages.OperationResponse, error) {
+    response, err := c.Result(c.send(pid, msg).Result())
+    if err != nil {
+        return nil, err
+    }
+    return response, nil
+}
+
+// retry is request for idempotent messages, backing off between attempts.
+// Create messages must carry an idempotency key.
+func (c *Client) retry(pid *actor.PID, msg interface{}) (*messages.OperationResponse, error) {
+    backoff := c.config.Backoff
+    for attempt := 0; ; attempt++ {
+        response, err := c.request(pid, msg)
+        if err == nil || attempt >= c.config.Retries || !retryable(err) {
+            return response, err
+        }
+
+        select {
+        case <-time.After(backoff):
+        case <-c.done:
+            return nil, err
+        }
+        backoff = min(backoff*2, c.config.MaxBackoff)
+    }
+}
+
+// Future sends a request message to the manager that handles it and returns
+// without waiting, for actors that continue with context.ReenterAfter. The
+// future is bounded by the configured timeout but never retried.
+func (c *Client) Future(msg interface{}) *actor.Future {
+    return c.send(c.managerFor(msg), msg)
+}
+
+// send sends msg to pid, in a span that ends when the future completes if
+// the client traces.
+func (c *Client) send(pid *actor.PID, msg interface{}) *actor.Future {
+    if c.config.Tracer == nil {
+        return c.root.RequestFuture(pid, msg, c.config.Timeout)
+    }
+    name := fmt.Sprintf("%T", msg)
+    if m, ok := msg.(proto.Message); ok {
+        name = string(m.ProtoReflect().Descriptor().Name())
+    }
+    ctx, span := c.config.Tracer.Start(context.Background(), "client/"+name,
+        trace.WithSpanKind(trace.SpanKindClient),
+        trace.WithAttributes(attribute.String("actor", pid.Id), attribute.String("address", pid.Address)))
+    future := tracing.Sender(c.root.ActorSystem(), ctx).RequestFuture(pid, msg, c.config.Timeout)
+    go func() {
+        res, err := future.Result()
+        if response, ok := res.(*messages.OperationResponse); ok {
+            span.SetAttributes(attribute.String("code", response.Code.String()))
+            err = ResponseError(response)
+        }
+        tracing.End(span, err)
+    }()
+    return future
+}
+
+// Result turns the outcome of a Future into the OperationResponse, recording
+// whether the engine could be reached. Failed responses are returned as
+// errors together with the response.
+func (c *Client) Result(res interface{}, err error) (*messages.OperationResponse, error) {
+    if err != nil {
+        c.recordFailure()
+        return nil, err
+    }
+    c.recordSuccess()
+
+    response, ok := res.(*messages.OperationResponse)
+    if !ok {
+        return nil, fmt.Errorf("unexpected response %T", res)
+    }
+    return response, ResponseError(response)
+}
+
+func (c *Client) managerFor(msg interface{}) *actor.PID {
+    switch msg := msg.(type) {
+    case *messages.RegisterUserMsg, *messages.GetUserMsg, *messages.SaveItemMsg, *messages.SetPreferenceMsg:
+        return c.managers.UserManager
+    case *messages.CreateSubRedditMsg, *messages.JoinSubRedditMsg, *messages.LeaveSubRedditMsg,
+        *messages.GetSubRedditMsg, *messages.GetSubscriptionsMsg, *messages.UpdateSubredditSettingsMsg:
+        return c.managers.SubredditManager
+    case *messages.CreatePostMsg, *messages.GetPostMsg, *messages.GetFeedMsg:
+        return c.managers.PostManager
+    case *messages.CreateCommentMsg, *messages.GetCommentsMsg:
+        return c.managers.CommentManager
+    case *messages.SendDirectMessageMsg, *messages.GetDirectMessagesMsg:
+        return c.managers.MessageManager
+    case *messages.VoteMsg:
+        return c.voteManager(msg.ItemId)
+    }
+    panic(fmt.Sprintf("client: no manager handles %T", msg))
+}
+
+// voteManager is the post manager for post IDs and the comment manager for
+// everything else.
+func (c *Client) voteManager(itemID string) *actor.PID {
+    if strings.HasPrefix(itemID, "post_") {
+        return c.managers.PostManager
+    }
+    return c.managers.CommentManager
+}
+
+// NewIdempotencyKey returns a random key for one create request. Every
+// retry of the request must reuse it.
+func NewIdempotencyKey() string {
+    key := make([]byte, 16)
+    if _, err := rand.Read(key); err != nil {
+        panic(err)
+    }
+    return hex.EncodeToString(key)
+}
+
+func retryable(err error) bool {
+    return errors.Is(err, actor.ErrTimeout) ||
+        errors.Is(err, actor.ErrDeadLetter) ||
+        errors.Is(err, ErrRateLimited) ||
+        errors.Is(err, ErrOverloaded)
+}
+
+func (c *Client) recordSuccess() {
+    c.failures.Store(0)
+    c.setHealthy(true)
+}
+
+func (c *Client) recordFailure() {
+    if int(c.failures.Add(1)) >= c.config.UnhealthyAfter {
+        c.setHealthy(false)
+    }
+}
+
+func (c *Client) setHealthy(healthy bool) {
+    if c.healthy.Swap(healthy) != healthy && c.config.OnHealthChange != nil {
+        c.config.OnHealthChange(healthy)
+    }
+}
+
+func toPost(post *messages.Post) engine.Post {
+    return engine.Post{
+        ID:          post.Id,
+        AuthorID:    post.AuthorId,
+        SubredditID: post.Subreddit,
+        Title:       post.Title,
+        Content:     post.Content,
+        Score:       int(post.Upvotes - post.Downvotes),
+        CreatedAt:   post.Timestamp.AsTime(),
+    }
+}
+
+func toComments(comments []*messages.Comment) []engine.Comment {
+    converted := make([]engine.Comment, 0, len(comments))
+    for _, comment := range comments {
+        converted = append(converted, engine.Comment{
+            ID:        comment.Id,
+            PostID:    comment.PostId,
+            ParentID:  comment.ParentId,
+            AuthorID:  comment.AuthorId,
+            Content:   comment.Content,
+            Score:     int(comment.Upvotes - comment.Downvotes),
+            Replies:   toComments(comment.Children),
+            CreatedAt: comment.Timestamp.AsTime(),
+        })
+    }
+    return converted
+}
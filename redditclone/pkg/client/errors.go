@@ -0,0 +1,74 @@
+// pkg/client/errors.go
+package client
+
+import (
+    "errors"
+    "fmt"
+
+    "redditclone/internal/messages"
+    "redditclone/pkg/engine"
+)
+
+// Sentinel errors for each ErrorCode. Match them with errors.Is; use
+// errors.As with *Error for the details. Not found and already exists are
+// the same values the engine package uses.
+var (
+    ErrNotFound         = engine.ErrNotFound
+    ErrAlreadyExists    = engine.ErrAlreadyExists
+    ErrPermissionDenied = errors.New("permission denied")
+    ErrRateLimited      = errors.New("rate limited")
+    ErrInvalidArgument  = errors.New("invalid argument")
+    ErrUnauthenticated  = errors.New("unauthenticated")
+    ErrInternal         = errors.New("internal error")
+    ErrOverloaded       = errors.New("overloaded")
+)
+
+var codeErrors = map[messages.ErrorCode]error{
+    messages.ErrorCode_NOT_FOUND:         ErrNotFound,
+    messages.ErrorCode_ALREADY_EXISTS:    ErrAlreadyExists,
+    messages.ErrorCode_PERMISSION_DENIED: ErrPermissionDenied,
+    messages.ErrorCode_RATE_LIMITED:      ErrRateLimited,
+    messages.ErrorCode_INVALID_ARGUMENT:  ErrInvalidArgument,
+    messages.ErrorCode_UNAUTHENTICATED:   ErrUnauthenticated,
+    messages.ErrorCode_INTERNAL:          ErrInternal,
+    messages.ErrorCode_OVERLOADED:        ErrOverloaded,
+}
+
+// Error is a failed OperationResponse.
+type Error struct {
+    Code       messages.ErrorCode
+    Message    string
+    Field      string // request field at fault, if any
+    EntityType string
+    EntityID   string
+}
+
+func (e *Error) Error() string {
+    return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+    if err, ok := codeErrors[e.Code]; ok {
+        return err
+    }
+    return ErrInternal
+}
+
+// ResponseError returns nil for a successful response and an *Error for a
+// failed one.
+func ResponseError(response *messages.OperationResponse) error {
+    if response.Success {
+        return nil
+    }
+
+    err := &Error{
+        Code:    response.Code,
+        Message: response.Error,
+    }
+    if details := response.Details; details != nil {
+        err.Field = details.Field
+        err.EntityType = details.EntityType
+        err.EntityID = details.EntityId
+    }
+    return err
+}
@@ -0,0 +1,174 @@
+// pkg/clock/clock.go
+package clock
+
+import (
+    "sort"
+    "sync"
+    "time"
+)
+
+// Clock tells the time and runs timers by it. The managers, their grains
+// and the simulator take one rather than calling time.Now or starting
+// timers of their own, so tests and accelerated runs can decide what time
+// it is and when timers fire.
+type Clock interface {
+    Now() time.Time
+
+    // AfterFunc calls f once d has passed on the clock, unless the timer
+    // is stopped first.
+    AfterFunc(d time.Duration, f func()) Timer
+
+    // After sends the clock's time on the returned channel once d has
+    // passed on it.
+    After(d time.Duration) <-chan time.Time
+}
+
+// Timer is a pending call from AfterFunc.
+type Timer interface {
+    // Stop cancels the call. It returns false if the call has already
+    // been made or the timer already stopped.
+    Stop() bool
+}
+
+// Repeat calls f once first has passed on c and then every interval, until
+// the returned function is called.
+func Repeat(c Clock, first, interval time.Duration, f func()) (stop func()) {
+    var (
+        mu      sync.Mutex
+        timer   Timer
+        stopped bool
+    )
+    var fire func()
+    fire = func() {
+        f()
+        mu.Lock()
+        defer mu.Unlock()
+        if !stopped {
+            timer = c.AfterFunc(interval, fire)
+        }
+    }
+
+    mu.Lock()
+    timer = c.AfterFunc(first, fire)
+    mu.Unlock()
+    return func() {
+        mu.Lock()
+        defer mu.Unlock()
+        stopped = true
+        timer.Stop()
+    }
+}
+
+// Real is the system clock.
+type Real struct{}
+
+func (Real) Now() time.Time {
+    return time.Now()
+}
+
+// AfterFunc calls f in its own goroutine, as time.AfterFunc does.
+func (Real) AfterFunc(d time.Duration, f func()) Timer {
+    return time.AfterFunc(d, f)
+}
+
+func (Real) After(d time.Duration) <-chan time.Time {
+    return time.After(d)
+}
+
+// Fake is a clock that only moves when told to. It is safe for concurrent
+// use, so one fake can drive every manager at once.
+type Fake struct {
+    mu     sync.Mutex
+    now    time.Time
+    timers []*fakeTimer
+}
+
+// NewFake returns a fake clock stopped at start.
+func NewFake(start time.Time) *Fake {
+    return &Fake{now: start}
+}
+
+func (f *Fake) Now() time.Time {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    return f.now
+}
+
+// AfterFunc calls f when Advance or Set moves the clock to d from now or
+// beyond, on the goroutine that moved it.
+func (f *Fake) AfterFunc(d time.Duration, call func()) Timer {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    timer := &fakeTimer{clock: f, at: f.now.Add(d), call: call}
+    f.timers = append(f.timers, timer)
+    return timer
+}
+
+// After sends on the returned channel when Advance or Set moves the clock
+// to d from now or beyond. The channel is buffered, so moving the clock
+// never waits for it to be read.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+    fired := make(chan time.Time, 1)
+    f.AfterFunc(d, func() { fired <- f.Now() })
+    return fired
+}
+
+// Advance moves the clock on by d, firing the timers due on the way in the
+// order they are due.
+func (f *Fake) Advance(d time.Duration) {
+    f.mu.Lock()
+    target := f.now.Add(d)
+    f.mu.Unlock()
+    f.Set(target)
+}
+
+// Set moves the clock to t, which may be in its past, firing the timers due
+// by then in the order they are due. Each one sees Now as the time it was
+// due, and timers they start fire too if they are due by t.
+func (f *Fake) Set(t time.Time) {
+    for {
+        f.mu.Lock()
+        timer := f.next(t)
+        if timer == nil {
+            f.now = t
+            f.mu.Unlock()
+            return
+        }
+        if timer.at.After(f.now) {
+            f.now = timer.at
+        }
+        f.mu.Unlock()
+        timer.call()
+    }
+}
+
+// next removes and returns the earliest timer due by t, or nil if none is.
+func (f *Fake) next(t time.Time) *fakeTimer {
+    sort.SliceStable(f.timers, func(i, j int) bool {
+        return f.timers[i].at.Before(f.timers[j].at)
+    })
+    if len(f.timers) == 0 || f.timers[0].at.After(t) {
+        return nil
+    }
+    timer := f.timers[0]
+    f.timers = f.timers[1:]
+    return timer
+}
+
+type fakeTimer struct {
+    clock *Fake
+    at    time.Time
+    call  func()
+}
+
+func (t *fakeTimer) Stop() bool {
+    t.clock.mu.Lock()
+    defer t.clock.mu.Unlock()
+    for i, timer := range t.clock.timers {
+        if timer == t {
+            t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,88 @@
+// pkg/clock/clock_test.go
+package clock_test
+
+import (
+    "reflect"
+    "testing"
+    "time"
+
+    "redditclone/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeFiresTimersInOrder(t *testing.T) {
+    c := clock.NewFake(start)
+    var fired []time.Duration
+    for _, d := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+        c.AfterFunc(d, func() { fired = append(fired, c.Now().Sub(start)) })
+    }
+
+    c.Advance(1500 * time.Millisecond)
+    if want := []time.Duration{time.Second}; !reflect.DeepEqual(fired, want) {
+        t.Fatalf("after 1.5s fired at %v, want %v", fired, want)
+    }
+    c.Advance(time.Minute)
+    if want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}; !reflect.DeepEqual(fired, want) {
+        t.Fatalf("after 61.5s fired at %v, want %v", fired, want)
+    }
+    if got, want := c.Now(), start.Add(61500*time.Millisecond); !got.Equal(want) {
+        t.Errorf("Now = %v, want %v", got, want)
+    }
+}
+
+func TestFakeAfter(t *testing.T) {
+    c := clock.NewFake(start)
+    fired := c.After(time.Second)
+
+    c.Advance(999 * time.Millisecond)
+    select {
+    case at := <-fired:
+        t.Fatalf("fired at %v, before it was due", at)
+    default:
+    }
+    c.Advance(time.Minute)
+    select {
+    case at := <-fired:
+        if want := start.Add(time.Second); !at.Equal(want) {
+            t.Errorf("fired at %v, want %v", at, want)
+        }
+    default:
+        t.Fatal("did not fire once due")
+    }
+}
+
+func TestFakeStop(t *testing.T) {
+    c := clock.NewFake(start)
+    fired := false
+    timer := c.AfterFunc(time.Second, func() { fired = true })
+
+    if !timer.Stop() {
+        t.Error("Stop of a pending timer = false, want true")
+    }
+    c.Advance(time.Minute)
+    if fired {
+        t.Error("stopped timer fired")
+    }
+    if timer.Stop() {
+        t.Error("second Stop = true, want false")
+    }
+}
+
+func TestRepeat(t *testing.T) {
+    c := clock.NewFake(start)
+    var fired []time.Duration
+    stop := clock.Repeat(c, time.Second, 10*time.Second, func() {
+        fired = append(fired, c.Now().Sub(start))
+    })
+
+    c.Advance(25 * time.Second)
+    if want := []time.Duration{time.Second, 11 * time.Second, 21 * time.Second}; !reflect.DeepEqual(fired, want) {
+        t.Fatalf("fired at %v, want %v", fired, want)
+    }
+    stop()
+    c.Advance(time.Minute)
+    if len(fired) != 3 {
+        t.Errorf("fired %d times after stop, want none", len(fired)-3)
+    }
+}
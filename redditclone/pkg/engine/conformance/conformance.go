@@ -0,0 +1,464 @@
+// pkg/engine/conformance/conformance.go
+package conformance
+
+import (
+    "errors"
+    "fmt"
+    "io"
+
+    "redditclone/pkg/engine"
+)
+
+// Case is one conformance check. It gets a fresh engine and returns the
+// first way the engine diverged from the engine.Engine contract.
+type Case struct {
+    Name string
+    Run  func(e engine.Engine) error
+}
+
+type Result struct {
+    Name string
+    Err  error
+}
+
+// Cases every backend must pass
+var Cases = []Case{
+    {"duplicate usernames", duplicateUsernames},
+    {"duplicate subreddits", duplicateSubreddits},
+    {"missing subreddits", missingSubreddits},
+    {"missing posts and comments", missingPosts},
+    {"nested replies", nestedReplies},
+    {"post vote changes", postVoteChanges},
+    {"comment vote changes", commentVoteChanges},
+    {"direct message ordering", directMessageOrdering},
+    {"feed", feed},
+}
+
+// Run runs every case against its own engine from newEngine.
+func Run(newEngine func() (engine.Engine, error)) []Result {
+    results := make([]Result, 0, len(Cases))
+    for _, c := range Cases {
+        e, err := newEngine()
+        if err != nil {
+            results = append(results, Result{Name: c.Name, Err: fmt.Errorf("starting engine: %w", err)})
+            continue
+        }
+        results = append(results, Result{Name: c.Name, Err: c.Run(e)})
+        e.Close()
+    }
+    return results
+}
+
+func duplicateUsernames(e engine.Engine) error {
+    alice, err := e.RegisterUser("alice")
+    if err != nil {
+        return fmt.Errorf("registering alice: %w", err)
+    }
+    if _, err := e.RegisterUser("alice"); !errors.Is(err, engine.ErrAlreadyExists) {
+        return fmt.Errorf("registering alice twice: got %v, want %v", err, engine.ErrAlreadyExists)
+    }
+
+    bob, err := e.RegisterUser("bob")
+    if err != nil {
+        return fmt.Errorf("registering bob: %w", err)
+    }
+    if bob == alice {
+        return fmt.Errorf("alice and bob were both given ID %q", alice)
+    }
+
+    user, err := e.GetUser(alice)
+    if err != nil {
+        return fmt.Errorf("getting alice: %w", err)
+    }
+    if user.Username != "alice" {
+        return fmt.Errorf("user %q has username %q, want alice", alice, user.Username)
+    }
+    return nil
+}
+
+func duplicateSubreddits(e engine.Engine) error {
+    users, err := register(e, "alice")
+    if err != nil {
+        return err
+    }
+    if _, err := e.CreateSubreddit(users[0], "golang", "All about Go"); err != nil {
+        return fmt.Errorf("creating golang: %w", err)
+    }
+    if _, err := e.CreateSubreddit(users[0], "golang", "Again"); !errors.Is(err, engine.ErrAlreadyExists) {
+        return fmt.Errorf("creating golang twice: got %v, want %v", err, engine.ErrAlreadyExists)
+    }
+    return nil
+}
+
+func missingSubreddits(e engine.Engine) error {
+    users, err := register(e, "alice")
+    if err != nil {
+        return err
+    }
+    alice := users[0]
+
+    if err := e.JoinSubreddit(alice, "missing"); !errors.Is(err, engine.ErrNotFound) {
+        return fmt.Errorf("joining a missing subreddit: got %v, want %v", err, engine.ErrNotFound)
+    }
+    if err := e.LeaveSubreddit(alice, "missing"); !errors.Is(err, engine.ErrNotFound) {
+        return fmt.Errorf("leaving a missing subreddit: got %v, want %v", err, engine.ErrNotFound)
+    }
+    if _, err := e.CreatePost(alice, "missing", "Title", "Content"); !errors.Is(err, engine.ErrNotFound) {
+        return fmt.Errorf("posting to a missing subreddit: got %v, want %v", err, engine.ErrNotFound)
+    }
+    return nil
+}
+
+func missingPosts(e engine.Engine) error {
+    users, err := register(e, "alice")
+    if err != nil {
+        return err
+    }
+    alice := users[0]
+
+    subreddit, err := e.CreateSubreddit(alice, "golang", "")
+    if err != nil {
+        return fmt.Errorf("creating golang: %w", err)
+    }
+    post, err := e.CreatePost(alice, subreddit, "Title", "Content")
+    if err != nil {
+        return fmt.Errorf("creating post: %w", err)
+    }
+
+    if _, err := e.GetPost("missing"); !errors.Is(err, engine.ErrNotFound) {
+        return fmt.Errorf("getting a missing post: got %v, want %v", err, engine.ErrNotFound)
+    }
+    if _, err := e.GetComments("missing"); !errors.Is(err, engine.ErrNotFound) {
+        return fmt.Errorf("getting comments of a missing post: got %v, want %v", err, engine.ErrNotFound)
+    }
+    if _, err := e.CreateComment(alice, "missing", "", "Comment"); !errors.Is(err, engine.ErrNotFound) {
+        return fmt.Errorf("commenting on a missing post: got %v, want %v", err, engine.ErrNotFound)
+    }
+    if _, err := e.CreateComment(alice, post, "missing", "Reply"); !errors.Is(err, engine.ErrNotFound) {
+        return fmt.Errorf("replying to a missing comment: got %v, want %v", err, engine.ErrNotFound)
+    }
+    if err := e.Vote(alice, "missing", true); !errors.Is(err, engine.ErrNotFound) {
+        return fmt.Errorf("voting on a missing item: got %v, want %v", err, engine.ErrNotFound)
+    }
+    return nil
+}
+
+func nestedReplies(e engine.Engine) error {
+    users, err := register(e, "alice", "bob")
+    if err != nil {
+        return err
+    }
+    alice, bob := users[0], users[1]
+
+    subreddit, err := e.CreateSubreddit(alice, "golang", "")
+    if err != nil {
+        return fmt.Errorf("creating golang: %w", err)
+    }
+    post, err := e.CreatePost(alice, subreddit, "Actors", "Actor models are awesome!")
+    if err != nil {
+        return fmt.Errorf("creating post: %w", err)
+    }
+    other, err := e.CreatePost(bob, subreddit, "Channels", "Channels too")
+    if err != nil {
+        return fmt.Errorf("creating second post: %w", err)
+    }
+
+    // first
+    //   reply
+    //     nested
+    // second
+    first, err := e.CreateComment(bob, post, "", "first")
+    if err != nil {
+        return fmt.Errorf("creating first comment: %w", err)
+    }
+    reply, err := e.CreateComment(alice, post, first, "reply")
+    if err != nil {
+        return fmt.Errorf("replying to first comment: %w", err)
+    }
+    if _, err := e.CreateComment(bob, post, reply, "nested"); err != nil {
+        return fmt.Errorf("replying to reply: %w", err)
+    }
+    if _, err := e.CreateComment(alice, post, "", "second"); err != nil {
+        return fmt.Errorf("creating second comment: %w", err)
+    }
+
+    if _, err := e.CreateComment(bob, other, first, "elsewhere"); !errors.Is(err, engine.ErrNotFound) {
+        return fmt.Errorf("replying to a comment on another post: got %v, want %v", err, engine.ErrNotFound)
+    }
+
+    comments, err := e.GetComments(post)
+    if err != nil {
+        return fmt.Errorf("getting comments: %w", err)
+    }
+    want := "[first [reply [nested]] second]"
+    if got := formatTree(comments); got != want {
+        return fmt.Errorf("comment tree is %s, want %s", got, want)
+    }
+    if comments[0].Replies[0].ParentID != first || comments[0].ParentID != "" {
+        return fmt.Errorf("reply parent IDs are wrong: %+v", comments)
+    }
+
+    if comments, err := e.GetComments(other); err != nil || len(comments) != 0 {
+        return fmt.Errorf("second post has comments %s, %v; want none", formatTree(comments), err)
+    }
+    return nil
+}
+
+func postVoteChanges(e engine.Engine) error {
+    users, err := register(e, "alice", "bob", "carol")
+    if err != nil {
+        return err
+    }
+    alice, bob, carol := users[0], users[1], users[2]
+
+    subreddit, err := e.CreateSubreddit(alice, "golang", "")
+    if err != nil {
+        return fmt.Errorf("creating golang: %w", err)
+    }
+    post, err := e.CreatePost(alice, subreddit, "Title", "Content")
+    if err != nil {
+        return fmt.Errorf("creating post: %w", err)
+    }
+
+    steps := []struct {
+        voter  string
+        upvote bool
+        score  int
+    }{
+        {bob, true, 1},
+        {bob, true, 1},    // repeated votes count once
+        {bob, false, -1},  // a changed vote replaces the old one
+        {carol, false, -2},
+        {carol, true, 0},
+    }
+    for i, step := range steps {
+        if err := e.Vote(step.voter, post, step.upvote); err != nil {
+            return fmt.Errorf("vote %d: %w", i+1, err)
+        }
+        got, err := e.GetPost(post)
+        if err != nil {
+            return fmt.Errorf("getting post after vote %d: %w", i+1, err)
+        }
+        if got.Score != step.score {
+            return fmt.Errorf("after vote %d post score is %d, want %d", i+1, got.Score, step.score)
+        }
+        if err := expectKarma(e, alice, step.score); err != nil {
+            return fmt.Errorf("after vote %d: %w", i+1, err)
+        }
+    }
+    return nil
+}
+
+func commentVoteChanges(e engine.Engine) error {
+    users, err := register(e, "alice", "bob", "carol")
+    if err != nil {
+        return err
+    }
+    alice, bob, carol := users[0], users[1], users[2]
+
+    subreddit, err := e.CreateSubreddit(alice, "golang", "")
+    if err != nil {
+        return fmt.Errorf("creating golang: %w", err)
+    }
+    post, err := e.CreatePost(alice, subreddit, "Title", "Content")
+    if err != nil {
+        return fmt.Errorf("creating post: %w", err)
+    }
+    comment, err := e.CreateComment(carol, post, "", "Comment")
+    if err != nil {
+        return fmt.Errorf("creating comment: %w", err)
+    }
+
+    for _, voter := range []string{alice, bob} {
+        if err := e.Vote(voter, comment, true); err != nil {
+            return fmt.Errorf("upvoting comment: %w", err)
+        }
+    }
+    if err := e.Vote(bob, comment, false); err != nil {
+        return fmt.Errorf("downvoting comment: %w", err)
+    }
+
+    comments, err := e.GetComments(post)
+    if err != nil {
+        return fmt.Errorf("getting comments: %w", err)
+    }
+    if len(comments) != 1 || comments[0].Score != 0 {
+        return fmt.Errorf("comments are %+v, want one comment with score 0", comments)
+    }
+    if err := expectKarma(e, carol, 0); err != nil {
+        return err
+    }
+    return expectKarma(e, alice, 0)
+}
+
+func directMessageOrdering(e engine.Engine) error {
+    users, err := register(e, "alice", "bob", "carol")
+    if err != nil {
+        return err
+    }
+    alice, bob, carol := users[0], users[1], users[2]
+
+    sends := []struct{ from, to, content string }{
+        {alice, carol, "one"},
+        {bob, carol, "two"},
+        {carol, alice, "reply"},
+        {alice, carol, "three"},
+    }
+    ids := make(map[string]bool)
+    for _, send := range sends {
+        id, err := e.SendDirectMessage(send.from, send.to, send.content)
+        if err != nil {
+            return fmt.Errorf("sending %q: %w", send.content, err)
+        }
+        if ids[id] {
+            return fmt.Errorf("message ID %q was used twice", id)
+        }
+        ids[id] = true
+    }
+
+    inbox, err := e.GetDirectMessages(carol)
+    if err != nil {
+        return fmt.Errorf("getting carol's messages: %w", err)
+    }
+    if got, want := formatInbox(inbox), "[one two three]"; got != want {
+        return fmt.Errorf("carol's inbox is %s, want %s", got, want)
+    }
+    if inbox[1].FromID != bob || inbox[1].ToID != carol {
+        return fmt.Errorf("message %q is from %q to %q, want from %q to %q",
+            inbox[1].Content, inbox[1].FromID, inbox[1].ToID, bob, carol)
+    }
+
+    inbox, err = e.GetDirectMessages(alice)
+    if err != nil {
+        return fmt.Errorf("getting alice's messages: %w", err)
+    }
+    if got, want := formatInbox(inbox), "[reply]"; got != want {
+        return fmt.Errorf("alice's inbox is %s, want %s", got, want)
+    }
+    return nil
+}
+
+func feed(e engine.Engine) error {
+    users, err := register(e, "alice", "bob")
+    if err != nil {
+        return err
+    }
+    alice, bob := users[0], users[1]
+
+    golang, err := e.CreateSubreddit(alice, "golang", "")
+    if err != nil {
+        return fmt.Errorf("creating golang: %w", err)
+    }
+    rust, err := e.CreateSubreddit(alice, "rust", "")
+    if err != nil {
+        return fmt.Errorf("creating rust: %w", err)
+    }
+    if err := e.JoinSubreddit(bob, golang); err != nil {
+        return fmt.Errorf("joining golang: %w", err)
+    }
+
+    posts := []struct{ subreddit, content string }{
+        {golang, "popular"},
+        {golang, "older"},
+        {rust, "elsewhere"},
+        {golang, "newer"},
+    }
+    ids := make([]string, len(posts))
+    for i, post := range posts {
+        ids[i], err = e.CreatePost(alice, post.subreddit, "Title", post.content)
+        if err != nil {
+            return fmt.Errorf("creating post %q: %w", post.content, err)
+        }
+    }
+    if err := e.Vote(bob, ids[0], true); err != nil {
+        return fmt.Errorf("upvoting: %w", err)
+    }
+
+    got, err := e.GetFeed(bob)
+    if err != nil {
+        return fmt.Errorf("getting feed: %w", err)
+    }
+    if formatted, want := formatFeed(got), "[popular newer older]"; formatted != want {
+        return fmt.Errorf("feed is %s, want %s", formatted, want)
+    }
+
+    if err := e.LeaveSubreddit(bob, golang); err != nil {
+        return fmt.Errorf("leaving golang: %w", err)
+    }
+    got, err = e.GetFeed(bob)
+    if err != nil {
+        return fmt.Errorf("getting feed after leaving: %w", err)
+    }
+    if len(got) != 0 {
+        return fmt.Errorf("feed after leaving is %s, want empty", formatFeed(got))
+    }
+    return nil
+}
+
+func register(e engine.Engine, usernames ...string) ([]string, error) {
+    ids := make([]string, len(usernames))
+    for i, username := range usernames {
+        id, err := e.RegisterUser(username)
+        if err != nil {
+            return nil, fmt.Errorf("registering %s: %w", username, err)
+        }
+        ids[i] = id
+    }
+    return ids, nil
+}
+
+func expectKarma(e engine.Engine, userID string, karma int) error {
+    user, err := e.GetUser(userID)
+    if err != nil {
+        return fmt.Errorf("getting user %q: %w", userID, err)
+    }
+    if user.Karma != karma {
+        return fmt.Errorf("%s has karma %d, want %d", user.Username, user.Karma, karma)
+    }
+    return nil
+}
+
+// formatTree renders comment contents with replies in nested brackets
+func formatTree(comments []engine.Comment) string {
+    s := "["
+    for i, comment := range comments {
+        if i > 0 {
+            s += " "
+        }
+        s += comment.Content
+        if len(comment.Replies) > 0 {
+            s += " " + formatTree(comment.Replies)
+        }
+    }
+    return s + "]"
+}
+
+func formatInbox(inbox []engine.DirectMessage) string {
+    contents := make([]string, len(inbox))
+    for i, message := range inbox {
+        contents[i] = message.Content
+    }
+    return fmt.Sprint(contents)
+}
+
+func formatFeed(posts []engine.Post) string {
+    contents := make([]string, len(posts))
+    for i, post := range posts {
+        contents[i] = post.Content
+    }
+    return fmt.Sprint(contents)
+}
+
+// Print writes one line per result and reports whether every case passed.
+func Print(w io.Writer, backend string, results []Result) bool {
+    passed := true
+    for _, result := range results {
+        if result.Err != nil {
+            passed = false
+            fmt.Fprintf(w, "FAIL %s: %s: %v\n", backend, result.Name, result.Err)
+        } else {
+            fmt.Fprintf(w, "ok   %s: %s\n", backend, result.Name)
+        }
+    }
+    return passed
+}
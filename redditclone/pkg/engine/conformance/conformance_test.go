@@ -0,0 +1,26 @@
+// pkg/engine/conformance/conformance_test.go
+package conformance_test
+
+import (
+    "testing"
+
+    "redditclone/pkg/engine/conformance"
+    "redditclone/pkg/engine/managers"
+)
+
+// TestManagers runs the suite against the manager actors, the backend this
+// module has; the root module's engines package runs it against the rest.
+func TestManagers(t *testing.T) {
+    for _, c := range conformance.Cases {
+        t.Run(c.Name, func(t *testing.T) {
+            e, err := managers.New()
+            if err != nil {
+                t.Fatalf("starting engine: %v", err)
+            }
+            defer e.Close()
+            if err := c.Run(e); err != nil {
+                t.Error(err)
+            }
+        })
+    }
+}
@@ -0,0 +1,88 @@
+// pkg/engine/engine.go
+package engine
+
+import (
+    "errors"
+    "time"
+)
+
+// Errors every Engine reports, so callers can use errors.Is regardless of
+// the backend
+var (
+    ErrNotFound      = errors.New("not found")
+    ErrAlreadyExists = errors.New("already exists")
+)
+
+type User struct {
+    ID       string
+    Username string
+    Karma    int
+}
+
+type Post struct {
+    ID          string
+    AuthorID    string
+    SubredditID string
+    Title       string
+    Content     string
+    Score       int
+    CreatedAt   time.Time
+}
+
+type Comment struct {
+    ID        string
+    PostID    string
+    ParentID  string // empty for top-level comments
+    AuthorID  string
+    Content   string
+    Score     int
+    Replies   []Comment
+    CreatedAt time.Time
+}
+
+type DirectMessage struct {
+    ID        string
+    FromID    string
+    ToID      string
+    Content   string
+    CreatedAt time.Time
+}
+
+// Engine is the set of platform operations and queries every backend
+// provides. IDs are opaque strings chosen by the backend.
+//
+// Votes are per user: voting again the same way changes nothing, voting the
+// other way replaces the earlier vote. Scores are upvotes minus downvotes
+// and an author's karma is the sum of the scores of everything they wrote.
+type Engine interface {
+    RegisterUser(username string) (string, error)
+    GetUser(userID string) (User, error)
+
+    CreateSubreddit(creatorID, name, description string) (string, error)
+    JoinSubreddit(userID, subredditID string) error
+    LeaveSubreddit(userID, subredditID string) error
+
+    CreatePost(authorID, subredditID, title, content string) (string, error)
+    GetPost(postID string) (Post, error)
+
+    // CreateComment adds a top-level comment when parentID is empty,
+    // otherwise a reply to a comment on the same post.
+    CreateComment(authorID, postID, parentID, content string) (string, error)
+
+    // GetComments returns a post's comments with replies nested, oldest
+    // first at every level.
+    GetComments(postID string) ([]Comment, error)
+
+    Vote(userID, itemID string, upvote bool) error
+
+    SendDirectMessage(fromID, toID, content string) (string, error)
+
+    // GetDirectMessages returns the messages a user received, oldest first.
+    GetDirectMessages(userID string) ([]DirectMessage, error)
+
+    // GetFeed returns the posts of the subreddits a user belongs to, highest
+    // score first and newest first among equal scores.
+    GetFeed(userID string) ([]Post, error)
+
+    Close()
+}
@@ -0,0 +1,58 @@
+// pkg/engine/managers/managers.go
+package managers
+
+import (
+    "github.com/asynkron/protoactor-go/actor"
+    "github.com/asynkron/protoactor-go/cluster"
+    "redditclone/internal/actors"
+    "redditclone/pkg/client"
+    "redditclone/pkg/clock"
+    "redditclone/pkg/engine"
+)
+
+// Engine runs the manager actors in a local actor system and exposes them
+// as an engine.Engine through a client.
+type Engine struct {
+    *client.Client
+    cluster *cluster.Cluster
+}
+
+var _ engine.Engine = (*Engine)(nil)
+
+func New() (*Engine, error) {
+    return NewWithClock(clock.Real{})
+}
+
+// NewWithClock is New with the managers reading the time from c, so a test
+// holding a clock.Fake decides when things happen.
+func NewWithClock(c clock.Clock) (*Engine, error) {
+    managerConfig := actors.DefaultManagerConfig()
+    managerConfig.Clock = c
+    return NewWithConfig(managerConfig)
+}
+
+// NewWithConfig is New with the managers spawned from managerConfig, for
+// instance to shard them. The subreddit grains run in a cluster of one.
+func NewWithConfig(managerConfig actors.ManagerConfig) (*Engine, error) {
+    system := actor.NewActorSystem()
+    managerConfig.Cluster = actors.StartLocalCluster(system, managerConfig)
+    managers, err := actors.SpawnManagers(system.Root, managerConfig)
+    if err != nil {
+        managerConfig.Cluster.Shutdown(false)
+        return nil, err
+    }
+
+    // In-process calls cannot get lost, so there is nothing to monitor
+    config := client.DefaultConfig()
+    config.HealthInterval = 0
+
+    return &Engine{
+        Client:  client.NewWithManagers(system.Root, managers, config),
+        cluster: managerConfig.Cluster,
+    }, nil
+}
+
+func (e *Engine) Close() {
+    e.Client.Close()
+    e.cluster.Shutdown(false)
+}
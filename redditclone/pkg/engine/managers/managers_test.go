@@ -0,0 +1,471 @@
+// pkg/engine/managers/managers_test.go
+package managers_test
+
+import (
+    "errors"
+    "fmt"
+    "math/rand/v2"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "redditclone/internal/actors"
+    "redditclone/internal/messages"
+    "redditclone/pkg/client"
+    "redditclone/pkg/clock"
+    "redditclone/pkg/engine"
+    "redditclone/pkg/engine/managers"
+    "google.golang.org/protobuf/proto"
+)
+
+// claimCounter counts the activations of each grain, which claim it.
+type claimCounter struct {
+    actors.GrainStore
+    mu     sync.Mutex
+    claims map[string]int
+}
+
+func (s *claimCounter) Claim(kind, identity string) (actors.Lease, error) {
+    s.mu.Lock()
+    s.claims[identity]++
+    s.mu.Unlock()
+    return s.GrainStore.Claim(kind, identity)
+}
+
+func (s *claimCounter) count(identity string) int {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.claims[identity]
+}
+
+func start(t *testing.T, configure func(*actors.ManagerConfig)) (*managers.Engine, *clock.Fake) {
+    t.Helper()
+    fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+    config := actors.DefaultManagerConfig()
+    config.Clock = fake
+    configure(&config)
+    e, err := managers.NewWithConfig(config)
+    if err != nil {
+        t.Fatalf("starting engine: %v", err)
+    }
+    t.Cleanup(e.Close)
+    return e, fake
+}
+
+func TestGrainsPassivateByClock(t *testing.T) {
+    store := &claimCounter{GrainStore: actors.NewMemoryStore(), claims: map[string]int{}}
+    e, fake := start(t, func(config *actors.ManagerConfig) {
+        config.Store = store
+        config.PassivateAfter = time.Minute
+    })
+
+    userID, err := e.RegisterUser("alice")
+    if err != nil {
+        t.Fatalf("RegisterUser: %v", err)
+    }
+    getUser := func() {
+        t.Helper()
+        if _, err := e.GetUser(userID); err != nil {
+            t.Fatalf("GetUser: %v", err)
+        }
+    }
+
+    // A request 45s in puts off passivation until 1m45s, however long the
+    // grain has been active
+    fake.Advance(45 * time.Second)
+    getUser()
+    fake.Advance(30 * time.Second)
+    getUser()
+    if n := store.count(userID); n != 1 {
+        t.Fatalf("grain activated %d times before going idle, want 1", n)
+    }
+
+    // A minute without requests passivates it; the next one activates it again
+    fake.Advance(time.Minute)
+    deadline := time.Now().Add(5 * time.Second)
+    for store.count(userID) < 2 {
+        if time.Now().After(deadline) {
+            t.Fatal("grain not passivated after a minute idle on the clock")
+        }
+        time.Sleep(10 * time.Millisecond)
+        getUser()
+    }
+}
+
+func TestIdempotencyWindowFollowsClock(t *testing.T) {
+    e, fake := start(t, func(config *actors.ManagerConfig) {
+        config.IdempotencyWindow = time.Minute
+    })
+    register := func() error {
+        _, err := e.Result(e.Future(&messages.RegisterUserMsg{Username: "bob", IdempotencyKey: "key"}).Result())
+        return err
+    }
+
+    if err := register(); err != nil {
+        t.Fatalf("RegisterUser: %v", err)
+    }
+    fake.Advance(59 * time.Second)
+    if err := register(); err != nil {
+        t.Fatalf("repeated RegisterUser within the window: %v, want the original response", err)
+    }
+    fake.Advance(time.Second)
+    if err := register(); !errors.Is(err, client.ErrAlreadyExists) {
+        t.Fatalf("repeated RegisterUser after the window: %v, want ErrAlreadyExists", err)
+    }
+}
+
+// failingPosts fails to save posts while failing is set.
+type failingPosts struct {
+    actors.GrainStore
+    failing atomic.Bool
+}
+
+func (s *failingPosts) Save(lease actors.Lease, kind, identity string, state proto.Message) error {
+    if kind == "post" && s.failing.Load() {
+        return errors.New("disk full")
+    }
+    return s.GrainStore.Save(lease, kind, identity, state)
+}
+
+func TestFailedPostSaveIsUnindexed(t *testing.T) {
+    store := &failingPosts{GrainStore: actors.NewMemoryStore()}
+    e, _ := start(t, func(config *actors.ManagerConfig) {
+        config.Store = store
+    })
+    userID, err := e.RegisterUser("carol")
+    if err != nil {
+        t.Fatalf("RegisterUser: %v", err)
+    }
+    subredditID, err := e.CreateSubreddit(userID, "golang", "")
+    if err != nil {
+        t.Fatalf("CreateSubreddit: %v", err)
+    }
+
+    store.failing.Store(true)
+    if _, err := e.CreatePost(userID, subredditID, "lost", "content"); !errors.Is(err, client.ErrInternal) {
+        t.Fatalf("CreatePost with a failing store: %v, want ErrInternal", err)
+    }
+    store.failing.Store(false)
+    postID, err := e.CreatePost(userID, subredditID, "kept", "content")
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+
+    // The failed post is taken out of the list after the fact
+    deadline := time.Now().Add(5 * time.Second)
+    for {
+        response, err := e.Result(e.Future(&messages.GetSubRedditMsg{Subreddit: subredditID}).Result())
+        if err != nil {
+            t.Fatalf("GetSubreddit: %v", err)
+        }
+        postIDs := response.GetSubreddit().PostIds
+        if len(postIDs) == 1 && postIDs[0] == postID {
+            return
+        }
+        if time.Now().After(deadline) {
+            t.Fatalf("subreddit lists posts %v, want only %s", postIDs, postID)
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+}
+
+func TestVoteStandsWithoutAuthor(t *testing.T) {
+    e, _ := start(t, func(*actors.ManagerConfig) {})
+    userID, err := e.RegisterUser("dave")
+    if err != nil {
+        t.Fatalf("RegisterUser: %v", err)
+    }
+    subredditID, err := e.CreateSubreddit(userID, "ghosts", "")
+    if err != nil {
+        t.Fatalf("CreateSubreddit: %v", err)
+    }
+    postID, err := e.CreatePost("u/ghost", subredditID, "boo", "content")
+    if err != nil {
+        t.Fatalf("CreatePost: %v", err)
+    }
+
+    if err := e.Vote(userID, postID, true); err != nil {
+        t.Fatalf("Vote on a post whose author has no grain: %v, want success", err)
+    }
+    post, err := e.GetPost(postID)
+    if err != nil {
+        t.Fatalf("GetPost: %v", err)
+    }
+    if post.Score != 1 {
+        t.Errorf("Score = %d, want 1", post.Score)
+    }
+}
+
+func TestJoinAndLeaveAsUnknownUser(t *testing.T) {
+    e, _ := start(t, func(*actors.ManagerConfig) {})
+    userID, err := e.RegisterUser("erin")
+    if err != nil {
+        t.Fatalf("RegisterUser: %v", err)
+    }
+    subredditID, err := e.CreateSubreddit(userID, "haunted", "")
+    if err != nil {
+        t.Fatalf("CreateSubreddit: %v", err)
+    }
+    if err := e.JoinSubreddit(userID, subredditID); err != nil {
+        t.Fatalf("JoinSubreddit: %v", err)
+    }
+
+    checkMembers := func(after string) {
+        t.Helper()
+        response, err := e.Result(e.Future(&messages.GetSubRedditMsg{Subreddit: subredditID}).Result())
+        if err != nil {
+            t.Fatalf("GetSubreddit: %v", err)
+        }
+        if members := response.GetSubreddit().Members; len(members) != 1 || !members[userID] {
+            t.Errorf("members after %s = %v, want only %s", after, members, userID)
+        }
+    }
+
+    if err := e.JoinSubreddit("u/ghost", subredditID); !errors.Is(err, client.ErrNotFound) {
+        t.Fatalf("JoinSubreddit as an unknown user: %v, want ErrNotFound", err)
+    }
+    checkMembers("join")
+    if err := e.LeaveSubreddit("u/ghost", subredditID); !errors.Is(err, client.ErrNotFound) {
+        t.Fatalf("LeaveSubreddit as an unknown user: %v, want ErrNotFound", err)
+    }
+    checkMembers("leave")
+}
+
+func TestDirectMessageFromUnknownUser(t *testing.T) {
+    e, _ := start(t, func(*actors.ManagerConfig) {})
+    userID, err := e.RegisterUser("frank")
+    if err != nil {
+        t.Fatalf("RegisterUser: %v", err)
+    }
+
+    if _, err := e.SendDirectMessage("u/ghost", userID, "boo"); !errors.Is(err, client.ErrNotFound) {
+        t.Fatalf("SendDirectMessage from an unknown user: %v, want ErrNotFound", err)
+    }
+    inbox, err := e.GetDirectMessages(userID)
+    if err != nil {
+        t.Fatalf("GetDirectMessages: %v", err)
+    }
+    if len(inbox) != 0 {
+        t.Errorf("inbox = %v, want it empty", inbox)
+    }
+}
+
+func TestSubredditSettingsAreForModerators(t *testing.T) {
+    store := actors.NewMemoryStore()
+    e, _ := start(t, func(config *actors.ManagerConfig) { config.Store = store })
+    moderatorID, err := e.RegisterUser("grace")
+    if err != nil {
+        t.Fatalf("RegisterUser: %v", err)
+    }
+    memberID, err := e.RegisterUser("heidi")
+    if err != nil {
+        t.Fatalf("RegisterUser: %v", err)
+    }
+    subredditID, err := e.CreateSubreddit(moderatorID, "rules", "")
+    if err != nil {
+        t.Fatalf("CreateSubreddit: %v", err)
+    }
+    if err := e.JoinSubreddit(memberID, subredditID); err != nil {
+        t.Fatalf("JoinSubreddit: %v", err)
+    }
+    update := func(userID string, settings *messages.SubredditSettings) error {
+        _, err := e.Result(e.Future(&messages.UpdateSubredditSettingsMsg{
+            Subreddit: subredditID,
+            UserId:    userID,
+            Settings:  settings,
+        }).Result())
+        return err
+    }
+
+    want := &messages.SubredditSettings{Rules: []string{"be kind", "no spoilers"}, Over_18: true}
+    if err := update(memberID, &messages.SubredditSettings{Rules: []string{"anything goes"}}); !errors.Is(err, client.ErrPermissionDenied) {
+        t.Fatalf("UpdateSubredditSettings as a member: %v, want ErrPermissionDenied", err)
+    }
+    if err := update(moderatorID, want); err != nil {
+        t.Fatalf("UpdateSubredditSettings as the moderator: %v", err)
+    }
+
+    response, err := e.Result(e.Future(&messages.GetSubRedditMsg{Subreddit: subredditID}).Result())
+    if err != nil {
+        t.Fatalf("GetSubreddit: %v", err)
+    }
+    if got := response.GetSubreddit().Settings; !proto.Equal(got, want) {
+        t.Errorf("settings = %v, want %v", got, want)
+    }
+    saved := &messages.SubReddit{}
+    if _, err := store.Load(actors.SubredditKind, subredditID, saved); err != nil {
+        t.Fatalf("Load: %v", err)
+    }
+    if !proto.Equal(saved.Settings, want) {
+        t.Errorf("saved settings = %v, want %v", saved.Settings, want)
+    }
+}
+
+// BenchmarkShards measures what splitting the post and comment managers
+// into shards buys. A shard handles one request at a time and saves what
+// it changes before taking the next, so with a store that makes saves wait
+// the way a disk or database does, one shard queues every post's saves
+// behind each other and more shards let them overlap:
+//
+//	go test ./pkg/engine/managers -run '^$' -bench Shards -cpu 8
+func BenchmarkShards(b *testing.B) {
+    requests := []struct {
+        name string
+        send func(e engine.Engine, f *shardFixture)
+    }{
+        {"post", func(e engine.Engine, f *shardFixture) {
+            author := rand.IntN(len(f.users))
+            e.CreatePost(f.users[author], f.subreddits[author%len(f.subreddits)], "title", "content")
+        }},
+        {"comment", func(e engine.Engine, f *shardFixture) {
+            e.CreateComment(f.user(), f.post(), "", "comment")
+        }},
+        {"vote", func(e engine.Engine, f *shardFixture) {
+            e.Vote(f.user(), f.post(), rand.IntN(2) == 0)
+        }},
+    }
+    for _, request := range requests {
+        for _, shards := range []int{1, 2, 4, 8} {
+            b.Run(fmt.Sprintf("%s/shards-%d", request.name, shards), func(b *testing.B) {
+                e, f := newShardFixture(b, shards)
+                defer e.Close()
+                b.ResetTimer()
+                b.RunParallel(func(pb *testing.PB) {
+                    for pb.Next() {
+                        request.send(e, f)
+                    }
+                })
+            })
+        }
+    }
+}
+
+// saveLatency is how long the benchmarks' store takes over a save.
+const saveLatency = 200 * time.Microsecond
+
+// slowStore is a store whose saves each take saveLatency, and which locks
+// one grain's entry at a time rather than the whole store, so saves for
+// different grains wait side by side and only the shards serialize them.
+type slowStore struct {
+    entries sync.Map // of *slowEntry, by kind and identity
+}
+
+type slowEntry struct {
+    mu    sync.Mutex
+    epoch int64
+    data  []byte
+}
+
+func (s *slowStore) entry(kind, identity string) *slowEntry {
+    entry, _ := s.entries.LoadOrStore(kind+"/"+identity, &slowEntry{})
+    return entry.(*slowEntry)
+}
+
+func (s *slowStore) Claim(kind, identity string) (actors.Lease, error) {
+    entry := s.entry(kind, identity)
+    entry.mu.Lock()
+    defer entry.mu.Unlock()
+    entry.epoch++
+    return actors.Lease{Kind: kind, Identity: identity, Epoch: entry.epoch}, nil
+}
+
+func (s *slowStore) Load(kind, identity string, state proto.Message) (bool, error) {
+    entry := s.entry(kind, identity)
+    entry.mu.Lock()
+    data := entry.data
+    entry.mu.Unlock()
+    if data == nil {
+        return false, nil
+    }
+    return true, proto.Unmarshal(data, state)
+}
+
+func (s *slowStore) Save(lease actors.Lease, kind, identity string, state proto.Message) error {
+    data, err := proto.Marshal(state)
+    if err != nil {
+        return err
+    }
+    time.Sleep(saveLatency)
+    claim := s.entry(lease.Kind, lease.Identity)
+    claim.mu.Lock()
+    fenced := claim.epoch != lease.Epoch
+    claim.mu.Unlock()
+    if fenced {
+        return actors.ErrFenced
+    }
+    entry := s.entry(kind, identity)
+    entry.mu.Lock()
+    defer entry.mu.Unlock()
+    entry.data = data
+    return nil
+}
+
+func (s *slowStore) Keys(kind string) ([]string, error) {
+    var keys []string
+    s.entries.Range(func(key, value interface{}) bool {
+        if identity, ok := strings.CutPrefix(key.(string), kind+"/"); ok && value.(*slowEntry).data != nil {
+            keys = append(keys, identity)
+        }
+        return true
+    })
+    return keys, nil
+}
+
+// shardFixture is the users, subreddits and posts a shard benchmark sends
+// its requests about. Each user belongs to one subreddit, and the posts
+// are spread over the shards by their IDs.
+type shardFixture struct {
+    users      []string
+    subreddits []string
+    posts      []string
+}
+
+func newShardFixture(b *testing.B, shards int) (*managers.Engine, *shardFixture) {
+    config := actors.DefaultManagerConfig()
+    config.Shards = shards
+    config.Store = &slowStore{}
+    e, err := managers.NewWithConfig(config)
+    if err != nil {
+        b.Fatal(err)
+    }
+
+    f := &shardFixture{}
+    for i := 0; i < 64; i++ {
+        user, err := e.RegisterUser(fmt.Sprintf("sharder_%d", i))
+        if err != nil {
+            b.Fatal(err)
+        }
+        f.users = append(f.users, user)
+    }
+    for i := 0; i < 16; i++ {
+        subreddit, err := e.CreateSubreddit(f.users[i], fmt.Sprintf("shards_%d", i), "")
+        if err != nil {
+            b.Fatal(err)
+        }
+        f.subreddits = append(f.subreddits, subreddit)
+    }
+    for i, user := range f.users {
+        if err := e.JoinSubreddit(user, f.subreddits[i%len(f.subreddits)]); err != nil {
+            b.Fatal(err)
+        }
+    }
+    for i := 0; i < 256; i++ {
+        author := i % len(f.users)
+        post, err := e.CreatePost(f.users[author], f.subreddits[author%len(f.subreddits)], "title", "content")
+        if err != nil {
+            b.Fatal(err)
+        }
+        f.posts = append(f.posts, post)
+    }
+    return e, f
+}
+
+func (f *shardFixture) user() string {
+    return f.users[rand.IntN(len(f.users))]
+}
+
+func (f *shardFixture) post() string {
+    return f.posts[rand.IntN(len(f.posts))]
+}